@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 
 	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/jobs"
+	"github.com/nikhilpratapgit/storex/mailer"
 	"github.com/nikhilpratapgit/storex/server"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
 )
 
 func getEnv(key, fallback string) string {
@@ -18,6 +24,15 @@ func getEnv(key, fallback string) string {
 }
 
 func main() {
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure mail: %v", err)
+	}
+	if logMailer, ok := mail.(mailer.LogMailer); ok && logMailer.Dir == "" {
+		fmt.Println("WARNING: MAILER=log without MAIL_DIR prints every mail, reset and invitation links included, to stdout; do not use it in production")
+	}
+	mailer.SetDefault(mail)
+
 	srv := server.SetupRoutes()
 
 	dbHost := getEnv("DB_HOST", "localhost")
@@ -28,7 +43,7 @@ func main() {
 	sslMode := getEnv("DB_SSLMODE", string(database.SSLModeDisable))
 	serverPort := getEnv("SERVER_PORT", "8080")
 
-	err := database.ConnectAndMigrate(
+	err = database.ConnectAndMigrate(
 		dbHost,
 		dbPort,
 		dbName,
@@ -37,7 +52,20 @@ func main() {
 		database.SSLMode(sslMode),
 	)
 	if err != nil {
-		fmt.Printf("failed while initialize and migrate database:%v", err)
+		fmt.Printf("failed while initialize and migrate database:%v\n", err)
+		fmt.Println("background jobs are not started without a database")
+	} else {
+		// secrets stored before encryption was enabled are sealed on startup
+		sealed, err := service.SealPlaintextSecrets()
+		switch {
+		case errors.Is(err, utils.ErrKeyringNotConfigured):
+			log.Fatal("asset specs hold plaintext secrets: configure MASTER_KEYS and MASTER_KEY_ID to encrypt them")
+		case err != nil:
+			fmt.Printf("failed to encrypt plaintext secrets: %v\n", err)
+		case sealed > 0:
+			fmt.Printf("encrypted plaintext secrets of %d assets\n", sealed)
+		}
+		jobs.Start(context.Background(), jobs.Default())
 	}
 	fmt.Println("server is running")
 	ServerErr := http.ListenAndServe(":8080", srv)
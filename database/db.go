@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 
@@ -58,7 +60,35 @@ func migrateUp(db *sqlx.DB) error {
 	fmt.Println("migrations applied successfully")
 	return nil
 }
-func Tx(fn func(tx *sqlx.Tx) error) error {
+
+// WithAdvisoryLock runs fn while holding the Postgres advisory lock named key, on a connection
+// of its own so the lock is shared by every server instance. It returns false without running
+// fn when another session holds the lock.
+func WithAdvisoryLock(ctx context.Context, key string, fn func() error) (bool, error) {
+	conn, err := Store.Connx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.GetContext(ctx, &locked, `SELECT pg_try_advisory_lock(hashtext($1))`, key); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			fmt.Printf("failed to release advisory lock %s: %v\n", key, err)
+			// the lock lives as long as the connection, so it must not go back to the pool
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+	return true, fn()
+}
+
+func Tx(fn func(tx *sqlx.Tx) error) (err error) {
 	tx, err := Store.Beginx()
 	if err != nil {
 		return fmt.Errorf("failed to start a transaction: %v", err)
@@ -66,12 +96,12 @@ func Tx(fn func(tx *sqlx.Tx) error) error {
 	defer func() {
 		if err != nil {
 			if rollBackErr := tx.Rollback(); rollBackErr != nil {
-				fmt.Println("failed to rollback tx : %s", rollBackErr)
+				fmt.Printf("failed to rollback tx : %s\n", rollBackErr)
 			}
 			return
 		}
 		if commitErr := tx.Commit(); commitErr != nil {
-			fmt.Println("failed to commit: %s", commitErr)
+			err = fmt.Errorf("failed to commit: %w", commitErr)
 		}
 	}()
 	err = fn(tx)
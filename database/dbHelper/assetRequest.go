@@ -0,0 +1,277 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func GetUserManager(tx *sqlx.Tx, userID string) (*string, error) {
+	SQL := `SELECT manager_id
+			FROM users
+			WHERE id=$1
+			`
+	var managerID *string
+	err := tx.Get(&managerID, SQL, userID)
+	return managerID, err
+}
+
+func UpdateUserManager(userID string, managerID *string) error {
+	SQL := `UPDATE users
+			SET manager_id=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := database.Store.Exec(SQL, userID, managerID)
+	return err
+}
+
+// IsAboveInChain tells whether userID is managerID or one of the managers above managerID, in
+// which case making managerID the manager of userID would close a loop.
+func IsAboveInChain(managerID, userID string) (bool, error) {
+	SQL := `WITH RECURSIVE chain AS (
+				SELECT id, manager_id
+				FROM users
+				WHERE id=$1
+				UNION
+				SELECT u.id, u.manager_id
+				FROM users u
+				JOIN chain c ON u.id=c.manager_id
+			)
+			SELECT count(*)>0
+			FROM chain
+			WHERE id=$2
+			`
+	var above bool
+	err := database.Store.Get(&above, SQL, managerID, userID)
+	return above, err
+}
+
+func ListApprovalSteps() ([]models.ApprovalStep, error) {
+	SQL := `SELECT position, approver_kind, approver_role
+			FROM asset_request_approval_steps
+			ORDER BY position
+			`
+	steps := make([]models.ApprovalStep, 0)
+	err := database.Store.Select(&steps, SQL)
+	return steps, err
+}
+
+// ReplaceApprovalSteps sets the approval chain of new requests, numbering the steps in order.
+func ReplaceApprovalSteps(tx *sqlx.Tx, steps []models.ApprovalStepRequest) error {
+	if _, err := tx.Exec(`DELETE FROM asset_request_approval_steps`); err != nil {
+		return err
+	}
+	SQL := `INSERT INTO asset_request_approval_steps (position, approver_kind, approver_role)
+			VALUES ($1,$2,NULLIF($3,''))
+			`
+	for i, step := range steps {
+		if _, err := tx.Exec(SQL, i+1, step.ApproverKind, step.ApproverRole); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CreateAssetRequest(tx *sqlx.Tx, requestedBy string, request models.CreateAssetRequestRequest) (string, error) {
+	SQL := `INSERT INTO asset_requests (requested_by, asset_type, justification, needed_by)
+			VALUES ($1,$2,$3,$4)
+			RETURNING id
+			`
+	var requestID string
+	err := tx.Get(&requestID, SQL, requestedBy, request.AssetType, request.Justification, request.NeededBy)
+	return requestID, err
+}
+
+// SnapshotApprovalSteps copies the configured approval chain onto a request. Manager steps are
+// resolved to the manager of the requester at the time of the request.
+func SnapshotApprovalSteps(tx *sqlx.Tx, requestID string, managerID *string) error {
+	SQL := `INSERT INTO asset_request_approvals (request_id, position, approver_kind, approver_role, approver_id)
+			SELECT $1, position, approver_kind, approver_role,
+			       CASE WHEN approver_kind='manager' THEN $2::uuid END
+			FROM asset_request_approval_steps
+			`
+	_, err := tx.Exec(SQL, requestID, managerID)
+	return err
+}
+
+// SkipManagerSteps marks the manager steps of a request skipped, for requesters without a manager.
+func SkipManagerSteps(tx *sqlx.Tx, requestID, comment string) (int64, error) {
+	SQL := `UPDATE asset_request_approvals
+			SET decision='skipped',
+			    decided_at=NOW(),
+			    comment=$2
+			WHERE request_id=$1
+			AND approver_kind='manager'
+			AND decision IS NULL
+			`
+	result, err := tx.Exec(SQL, requestID, comment)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const assetRequestSQL = `SELECT r.id, r.requested_by, u.name AS requested_by_name, r.asset_type, r.justification,
+			       r.needed_by, r.status, r.fulfilled_asset_id, r.created_at, r.updated_at, r.closed_at
+			FROM asset_requests r
+			JOIN users u ON u.id=r.requested_by
+			`
+
+// ListAssetRequests lists requests filtered by status and requester, each filter is skipped when empty.
+func ListAssetRequests(status, requestedBy string) ([]models.AssetRequest, error) {
+	SQL := assetRequestSQL + `WHERE ($1 = '' OR r.status::text=$1)
+			AND ($2 = '' OR r.requested_by::text=$2)
+			ORDER BY r.created_at DESC
+			`
+	requests := make([]models.AssetRequest, 0)
+	err := database.Store.Select(&requests, SQL, status, requestedBy)
+	return requests, err
+}
+
+// ListRequestsAwaitingApprover lists pending requests whose current step can be decided by the
+// user, either as the named manager or through their role. Users never approve their own requests.
+func ListRequestsAwaitingApprover(userID, role string) ([]models.AssetRequest, error) {
+	SQL := assetRequestSQL + `JOIN asset_request_approvals ara ON ara.request_id=r.id
+			WHERE r.status='pending'
+			AND r.requested_by<>$1
+			AND ara.decision IS NULL
+			AND ara.position=(
+				SELECT MIN(position)
+				FROM asset_request_approvals
+				WHERE request_id=r.id
+				AND decision IS NULL
+			)
+			AND ((ara.approver_kind='manager' AND ara.approver_id=$1)
+			     OR (ara.approver_kind='role' AND ara.approver_role=$2))
+			ORDER BY r.needed_by NULLS LAST, r.created_at
+			`
+	requests := make([]models.AssetRequest, 0)
+	err := database.Store.Select(&requests, SQL, userID, role)
+	return requests, err
+}
+
+func GetAssetRequest(requestID string) (models.AssetRequest, error) {
+	SQL := assetRequestSQL + `WHERE r.id=$1`
+	var request models.AssetRequest
+	err := database.Store.Get(&request, SQL, requestID)
+	return request, err
+}
+
+func GetAssetRequestForUpdate(tx *sqlx.Tx, requestID string) (models.AssetRequest, error) {
+	SQL := assetRequestSQL + `WHERE r.id=$1
+			FOR UPDATE OF r
+			`
+	var request models.AssetRequest
+	err := tx.Get(&request, SQL, requestID)
+	return request, err
+}
+
+func UpdateAssetRequestStatus(tx *sqlx.Tx, requestID, status string, closed bool) error {
+	SQL := `UPDATE asset_requests
+			SET status=$2,
+			    updated_at=NOW(),
+			    closed_at=CASE WHEN $3 THEN NOW() END
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, requestID, status, closed)
+	return err
+}
+
+func FulfilAssetRequest(tx *sqlx.Tx, requestID, assetID string) error {
+	SQL := `UPDATE asset_requests
+			SET status='fulfilled',
+			    fulfilled_asset_id=$2,
+			    updated_at=NOW(),
+			    closed_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, requestID, assetID)
+	return err
+}
+
+func ListAssetRequestApprovals(requestID string) ([]models.AssetRequestApproval, error) {
+	SQL := `SELECT id, request_id, position, approver_kind, approver_role, approver_id, decision,
+			       decided_by, decided_at, comment
+			FROM asset_request_approvals
+			WHERE request_id=$1
+			ORDER BY position
+			`
+	approvals := make([]models.AssetRequestApproval, 0)
+	err := database.Store.Select(&approvals, SQL, requestID)
+	return approvals, err
+}
+
+// CurrentApproval returns the first undecided step of a request, sql.ErrNoRows when every step is decided.
+func CurrentApproval(tx *sqlx.Tx, requestID string) (models.AssetRequestApproval, error) {
+	SQL := `SELECT id, request_id, position, approver_kind, approver_role, approver_id, decision,
+			       decided_by, decided_at, comment
+			FROM asset_request_approvals
+			WHERE request_id=$1
+			AND decision IS NULL
+			ORDER BY position
+			LIMIT 1
+			`
+	var approval models.AssetRequestApproval
+	err := tx.Get(&approval, SQL, requestID)
+	return approval, err
+}
+
+func DecideApproval(tx *sqlx.Tx, approvalID, decidedBy, decision, comment string) error {
+	SQL := `UPDATE asset_request_approvals
+			SET decision=$3,
+			    decided_by=$2,
+			    decided_at=NOW(),
+			    comment=NULLIF($4,'')
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, approvalID, decidedBy, decision, comment)
+	return err
+}
+
+// IsApproverOf tells whether the user is named as manager approver on a request.
+func IsApproverOf(requestID, userID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_request_approvals
+			WHERE request_id=$1
+			AND approver_id=$2
+			`
+	var approver bool
+	err := database.Store.Get(&approver, SQL, requestID, userID)
+	return approver, err
+}
+
+// CreateAssetRequestEvent adds to the status trail of a request. The actor is empty for events
+// raised by the system, and fromStatus for the creation of the request. Events of one transaction
+// are stamped with the clock rather than the transaction start, so they keep their order.
+func CreateAssetRequestEvent(tx *sqlx.Tx, requestID, actorID, event, fromStatus, toStatus, notes string) error {
+	SQL := `INSERT INTO asset_request_events (request_id, actor_id, event, from_status, to_status, notes, created_at)
+			VALUES ($1,NULLIF($2,'')::uuid,$3,NULLIF($4,'')::asset_request_status,$5,NULLIF($6,''),clock_timestamp())
+			`
+	_, err := tx.Exec(SQL, requestID, actorID, event, fromStatus, toStatus, notes)
+	return err
+}
+
+func ListAssetRequestEvents(requestID string) ([]models.AssetRequestEvent, error) {
+	SQL := `SELECT e.id, e.request_id, e.actor_id, u.name AS actor_name, e.event, e.from_status,
+			       e.to_status, e.notes, e.created_at
+			FROM asset_request_events e
+			LEFT JOIN users u ON u.id=e.actor_id
+			WHERE e.request_id=$1
+			ORDER BY e.created_at, e.id
+			`
+	events := make([]models.AssetRequestEvent, 0)
+	err := database.Store.Select(&events, SQL, requestID)
+	return events, err
+}
+
+func GetAssetTypeName(tx *sqlx.Tx, assetID string) (string, error) {
+	SQL := `SELECT type
+			FROM assets
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	var assetType string
+	err := tx.Get(&assetType, SQL, assetID)
+	return assetType, err
+}
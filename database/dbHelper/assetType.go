@@ -0,0 +1,113 @@
+package dbHelper
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateAssetType(name, label string, specSchema json.RawMessage, transferRequiresApproval bool) error {
+	SQL := `INSERT INTO asset_types (name, label, spec_schema, transfer_requires_approval)
+			VALUES ($1,$2,$3,$4)
+			`
+	_, err := database.Store.Exec(SQL, name, label, specSchema, transferRequiresApproval)
+	return err
+}
+
+func IsAssetTypeExist(name string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_types
+			WHERE name=$1
+			`
+	var exists bool
+	err := database.Store.Get(&exists, SQL, name)
+	return exists, err
+}
+
+func ListAssetTypes() ([]models.AssetType, error) {
+	SQL := `SELECT name, label, spec_schema, transfer_requires_approval, created_at, updated_at
+			FROM asset_types
+			WHERE archived_at IS NULL
+			ORDER BY name
+			`
+	assetTypes := make([]models.AssetType, 0)
+	err := database.Store.Select(&assetTypes, SQL)
+	return assetTypes, err
+}
+
+func GetAssetType(name string) (models.AssetType, error) {
+	SQL := `SELECT name, label, spec_schema, transfer_requires_approval, created_at, updated_at
+			FROM asset_types
+			WHERE name=$1
+			AND archived_at IS NULL
+			`
+	var assetType models.AssetType
+	err := database.Store.Get(&assetType, SQL, name)
+	return assetType, err
+}
+
+// LockAssetType locks an active asset type while its spec schema is changed.
+func LockAssetType(tx *sqlx.Tx, name string) error {
+	SQL := `SELECT name
+			FROM asset_types
+			WHERE name=$1
+			AND archived_at IS NULL
+			FOR UPDATE
+			`
+	var locked string
+	return tx.Get(&locked, SQL, name)
+}
+
+// ListAssetSpecsOfType returns the specs of every active asset of a type, locked against changes.
+func ListAssetSpecsOfType(tx *sqlx.Tx, assetType string) ([]json.RawMessage, error) {
+	SQL := `SELECT specs
+			FROM assets
+			WHERE type=$1
+			AND archived_at IS NULL
+			FOR SHARE
+			`
+	specs := make([]json.RawMessage, 0)
+	err := tx.Select(&specs, SQL, assetType)
+	return specs, err
+}
+
+func UpdateAssetType(tx *sqlx.Tx, name, label string, specSchema json.RawMessage, transferRequiresApproval bool) error {
+	SQL := `UPDATE asset_types
+			SET label=$2,
+			    spec_schema=$3,
+			    transfer_requires_approval=$4,
+			    updated_at=NOW()
+			WHERE name=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, name, label, specSchema, transferRequiresApproval)
+	return err
+}
+
+// ArchiveAssetType retires a type so no new assets can be created with it. Types still
+// used by active assets are kept.
+func ArchiveAssetType(name string) error {
+	SQL := `UPDATE asset_types
+			SET archived_at=NOW()
+			WHERE name=$1
+			AND archived_at IS NULL
+			AND NOT EXISTS (
+			    SELECT 1 FROM assets WHERE type=$1 AND archived_at IS NULL
+			)
+			`
+	result, err := database.Store.Exec(SQL, name)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("asset type not found or still in use")
+	}
+	return nil
+}
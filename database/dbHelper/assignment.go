@@ -0,0 +1,65 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateAssignment(tx *sqlx.Tx, assetID, assignedTo, assignedBy string) error {
+	SQL := `INSERT INTO asset_assignments (asset_id, assigned_to, assigned_by_id)
+			VALUES ($1,$2,$3)
+			`
+	_, err := tx.Exec(SQL, assetID, assignedTo, assignedBy)
+	return err
+}
+
+// CloseAssignment ends the open assignment of an asset, if any. receivedBy, condition
+// and notes are left empty when the asset is handed straight to someone else.
+func CloseAssignment(tx *sqlx.Tx, assetID, receivedBy, condition, notes string) error {
+	SQL := `UPDATE asset_assignments
+			SET assigned_until=NOW(),
+			    received_by_id=NULLIF($2,'')::uuid,
+			    return_condition=NULLIF($3,'')::asset_condition,
+			    return_notes=NULLIF($4,'')
+			WHERE asset_id=$1
+			AND assigned_until IS NULL
+			`
+	_, err := tx.Exec(SQL, assetID, receivedBy, condition, notes)
+	return err
+}
+
+const assignmentHistorySQL = `SELECT aa.id, aa.asset_id, a.serial_number, a.brand, a.model,
+			       aa.assigned_to, u.name AS assigned_to_name, aa.assigned_by_id,
+			       aa.assigned_from, aa.assigned_until, aa.received_by_id,
+			       aa.return_condition, aa.return_notes, aa.acknowledged_at
+			FROM asset_assignments aa
+			JOIN assets a ON a.id = aa.asset_id
+			JOIN users u ON u.id = aa.assigned_to
+			`
+
+// AssetTimeline lists every assignment of an asset. from and to, when set, keep only the
+// assignments that overlap that period.
+func AssetTimeline(assetID string, from, to *time.Time) ([]models.AssignmentHistory, error) {
+	SQL := assignmentHistorySQL + `WHERE aa.asset_id=$1
+			AND ($2::timestamptz IS NULL OR COALESCE(aa.assigned_until, NOW()) >= $2)
+			AND ($3::timestamptz IS NULL OR aa.assigned_from <= $3)
+			ORDER BY aa.assigned_from
+			`
+	history := make([]models.AssignmentHistory, 0)
+	err := database.Store.Select(&history, SQL, assetID, from, to)
+	return history, err
+}
+
+func UserAssetHistory(userID string, from, to *time.Time) ([]models.AssignmentHistory, error) {
+	SQL := assignmentHistorySQL + `WHERE aa.assigned_to=$1
+			AND ($2::timestamptz IS NULL OR COALESCE(aa.assigned_until, NOW()) >= $2)
+			AND ($3::timestamptz IS NULL OR aa.assigned_from <= $3)
+			ORDER BY aa.assigned_from
+			`
+	history := make([]models.AssignmentHistory, 0)
+	err := database.Store.Select(&history, SQL, userID, from, to)
+	return history, err
+}
@@ -0,0 +1,189 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateAuditCampaign(tx *sqlx.Tx, openedBy string, request models.OpenAuditRequest) (string, error) {
+	SQL := `INSERT INTO audit_campaigns (name, scope, scope_value, notes, opened_by)
+			VALUES ($1,$2,NULLIF($3,''),NULLIF($4,''),$5)
+			RETURNING id
+			`
+	var campaignID string
+	err := tx.Get(&campaignID, SQL, request.Name, request.Scope, request.ScopeValue, request.Notes, openedBy)
+	return campaignID, err
+}
+
+// SnapshotAuditItems records the assets in the scope of a campaign as they stand now, and
+// returns how many there are. Assets already disposed of, lost or stolen are not expected, nor
+// are assigned assets in a location scope since they are with their holder.
+func SnapshotAuditItems(tx *sqlx.Tx, campaignID, scope, scopeValue string) (int64, error) {
+	SQL := `INSERT INTO audit_items (campaign_id, asset_id, expected_status, expected_holder)
+			SELECT $1, id, status, assigned_to
+			FROM assets
+			WHERE archived_at IS NULL
+			AND status NOT IN ('disposed', 'lost', 'stolen')
+			AND (
+			    $2 = 'all'
+			    OR ($2 = 'type' AND type::text=$3)
+			    OR ($2 = 'owner' AND owner::text=$3)
+			    OR ($2 = 'location' AND location_id IN (SELECT location_subtree($3)))
+			)
+			`
+	result, err := tx.Exec(SQL, campaignID, scope, scopeValue)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const auditCampaignSQL = `SELECT c.id, c.name, c.scope, c.scope_value, c.notes, c.status, c.opened_by, c.opened_at,
+			       c.closed_by, c.closed_at, c.flagged_lost,
+			       count(i.asset_id) FILTER (WHERE i.expected) AS expected,
+			       count(i.asset_id) FILTER (WHERE i.expected AND i.result='found') AS found,
+			       count(i.asset_id) FILTER (WHERE i.result='missing') AS missing,
+			       count(i.asset_id) FILTER (WHERE i.result='mismatched') AS mismatched,
+			       count(i.asset_id) FILTER (WHERE i.result='pending') AS pending,
+			       count(i.asset_id) FILTER (WHERE NOT i.expected) AS unexpected
+			FROM audit_campaigns c
+			LEFT JOIN audit_items i ON i.campaign_id=c.id
+			`
+
+// ListAuditCampaigns lists campaigns filtered by status, skipped when empty.
+func ListAuditCampaigns(status string) ([]models.AuditCampaign, error) {
+	SQL := auditCampaignSQL + `WHERE ($1 = '' OR c.status::text=$1)
+			GROUP BY c.id
+			ORDER BY c.opened_at DESC
+			`
+	campaigns := make([]models.AuditCampaign, 0)
+	err := database.Store.Select(&campaigns, SQL, status)
+	return campaigns, err
+}
+
+func GetAuditCampaign(campaignID string) (models.AuditCampaign, error) {
+	SQL := auditCampaignSQL + `WHERE c.id=$1
+			GROUP BY c.id
+			`
+	var campaign models.AuditCampaign
+	err := database.Store.Get(&campaign, SQL, campaignID)
+	return campaign, err
+}
+
+// GetAuditStatusForUpdate locks the campaign row for the rest of the transaction and returns its status.
+func GetAuditStatusForUpdate(tx *sqlx.Tx, campaignID string) (string, error) {
+	SQL := `SELECT status
+			FROM audit_campaigns
+			WHERE id=$1
+			FOR UPDATE
+			`
+	var status string
+	err := tx.Get(&status, SQL, campaignID)
+	return status, err
+}
+
+const auditItemSQL = `SELECT i.asset_id, a.serial_number, a.type, a.brand, a.model, i.expected, i.expected_status,
+			       i.expected_holder, h.name AS expected_holder_name, i.result, i.notes, i.checked_by,
+			       i.checked_at, i.flagged_lost
+			FROM audit_items i
+			JOIN assets a ON a.id=i.asset_id
+			LEFT JOIN users h ON h.id=i.expected_holder
+			`
+
+// ListAuditItems lists the items of a campaign filtered by result, skipped when empty.
+func ListAuditItems(campaignID, result string) ([]models.AuditItem, error) {
+	SQL := auditItemSQL + `WHERE i.campaign_id=$1
+			AND ($2 = '' OR i.result::text=$2)
+			ORDER BY a.serial_number
+			`
+	items := make([]models.AuditItem, 0)
+	err := database.Store.Select(&items, SQL, campaignID, result)
+	return items, err
+}
+
+// ListAuditDiscrepancies lists the items of a campaign other than expected assets found.
+func ListAuditDiscrepancies(campaignID string) ([]models.AuditItem, error) {
+	SQL := auditItemSQL + `WHERE i.campaign_id=$1
+			AND (i.result<>'found' OR NOT i.expected)
+			ORDER BY i.result, a.serial_number
+			`
+	items := make([]models.AuditItem, 0)
+	err := database.Store.Select(&items, SQL, campaignID)
+	return items, err
+}
+
+// GetAssetIDBySerial returns the id of the asset with the serial number, archived or not.
+func GetAssetIDBySerial(tx *sqlx.Tx, serialNumber string) (string, error) {
+	SQL := `SELECT id
+			FROM assets
+			WHERE serial_number=$1
+			`
+	var assetID string
+	err := tx.Get(&assetID, SQL, serialNumber)
+	return assetID, err
+}
+
+// RecordAuditResult sets the result of an asset in a campaign. An asset outside the snapshot is
+// added as unexpected, with its current status and holder.
+func RecordAuditResult(tx *sqlx.Tx, campaignID, assetID, checkedBy string, request models.AuditResultRequest) error {
+	SQL := `INSERT INTO audit_items (campaign_id, asset_id, expected, expected_status, expected_holder,
+			                         result, notes, checked_by, checked_at)
+			SELECT $1, a.id, FALSE, a.status, a.assigned_to, $3, NULLIF($4,''), $5, NOW()
+			FROM assets a
+			WHERE a.id=$2
+			ON CONFLICT (campaign_id, asset_id) DO UPDATE
+			SET result=EXCLUDED.result,
+			    notes=EXCLUDED.notes,
+			    checked_by=EXCLUDED.checked_by,
+			    checked_at=EXCLUDED.checked_at
+			`
+	_, err := tx.Exec(SQL, campaignID, assetID, request.Result, request.Notes, checkedBy)
+	return err
+}
+
+func IsExpectedInAudit(tx *sqlx.Tx, campaignID, assetID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM audit_items
+			WHERE campaign_id=$1
+			AND asset_id=$2
+			AND expected
+			`
+	var expected bool
+	err := tx.Get(&expected, SQL, campaignID, assetID)
+	return expected, err
+}
+
+func ListMissingAuditAssets(tx *sqlx.Tx, campaignID string) ([]string, error) {
+	SQL := `SELECT asset_id
+			FROM audit_items
+			WHERE campaign_id=$1
+			AND result='missing'
+			ORDER BY asset_id
+			`
+	assetIDs := make([]string, 0)
+	err := tx.Select(&assetIDs, SQL, campaignID)
+	return assetIDs, err
+}
+
+func MarkAuditItemFlagged(tx *sqlx.Tx, campaignID, assetID string) error {
+	SQL := `UPDATE audit_items
+			SET flagged_lost=TRUE
+			WHERE campaign_id=$1
+			AND asset_id=$2
+			`
+	_, err := tx.Exec(SQL, campaignID, assetID)
+	return err
+}
+
+func CloseAuditCampaign(tx *sqlx.Tx, campaignID, closedBy string, flaggedLost bool) error {
+	SQL := `UPDATE audit_campaigns
+			SET status='closed',
+			    closed_by=$2,
+			    closed_at=NOW(),
+			    flagged_lost=$3
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, campaignID, closedBy, flaggedLost)
+	return err
+}
@@ -0,0 +1,131 @@
+package dbHelper
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateCustomField(request models.CustomFieldDefinitionRequest) (string, error) {
+	SQL := `INSERT INTO custom_field_definitions (entity, key, label, field_type, required, allowed_values)
+			VALUES ($1,$2,$3,$4,$5,$6)
+			RETURNING id
+			`
+	var fieldID string
+	err := database.Store.Get(&fieldID, SQL, request.Entity, request.Key, request.Label, request.FieldType, request.Required, pq.StringArray(request.AllowedValues))
+	return fieldID, err
+}
+
+func IsCustomFieldExist(entity, key string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM custom_field_definitions
+			WHERE entity=$1
+			AND key=$2
+			AND archived_at IS NULL
+			`
+	var exists bool
+	err := database.Store.Get(&exists, SQL, entity, key)
+	return exists, err
+}
+
+// ListCustomFields returns the active definitions of an entity, or of every entity when entity is empty.
+func ListCustomFields(entity string) ([]models.CustomFieldDefinition, error) {
+	SQL := `SELECT id, entity, key, label, field_type, required, allowed_values, created_at, updated_at
+			FROM custom_field_definitions
+			WHERE archived_at IS NULL
+			AND ($1 = '' OR entity::text=$1)
+			ORDER BY entity, key
+			`
+	definitions := make([]models.CustomFieldDefinition, 0)
+	err := database.Store.Select(&definitions, SQL, entity)
+	return definitions, err
+}
+
+func GetCustomFieldForUpdate(tx *sqlx.Tx, fieldID string) (models.CustomFieldDefinition, error) {
+	SQL := `SELECT id, entity, key, label, field_type, required, allowed_values, created_at, updated_at
+			FROM custom_field_definitions
+			WHERE id=$1
+			AND archived_at IS NULL
+			FOR UPDATE
+			`
+	var definition models.CustomFieldDefinition
+	err := tx.Get(&definition, SQL, fieldID)
+	return definition, err
+}
+
+// CountCustomFieldViolations counts the active records of the field's entity that a required
+// flag or a list of allowed values would reject. A nil allowedValues skips that check.
+func CountCustomFieldViolations(tx *sqlx.Tx, definition models.CustomFieldDefinition, required bool, allowedValues []string) (int, error) {
+	table := "assets"
+	if definition.Entity == "user" {
+		table = "users"
+	}
+	SQL := `SELECT count(*)
+			FROM ` + table + `
+			WHERE archived_at IS NULL
+			AND (
+			    ($2 AND COALESCE(jsonb_typeof(custom_fields->$1), 'null') = 'null')
+			    OR ($3 AND COALESCE(jsonb_typeof(custom_fields->$1), 'null') <> 'null'
+			        AND NOT (custom_fields->>$1 = ANY($4)))
+			)
+			`
+	var count int
+	err := tx.Get(&count, SQL, definition.Key, required, allowedValues != nil, pq.StringArray(allowedValues))
+	return count, err
+}
+
+func UpdateCustomField(tx *sqlx.Tx, fieldID string, request models.UpdateCustomFieldDefinitionRequest) error {
+	SQL := `UPDATE custom_field_definitions
+			SET label=$2,
+			    required=$3,
+			    allowed_values=$4,
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, fieldID, request.Label, request.Required, pq.StringArray(request.AllowedValues))
+	return err
+}
+
+func ArchiveCustomField(fieldID string) error {
+	SQL := `UPDATE custom_field_definitions
+			SET archived_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, fieldID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("custom field not found")
+	}
+	return nil
+}
+
+func UpdateUserCustomFields(userID string, customFields json.RawMessage) error {
+	SQL := `UPDATE users
+			SET custom_fields=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, userID, customFields)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func ListDepreciationPolicies() ([]models.DepreciationPolicy, error) {
+	SQL := `SELECT asset_type, method, useful_life_months, salvage_percent, declining_rate, updated_at
+			FROM depreciation_policies
+			ORDER BY asset_type
+			`
+	policies := make([]models.DepreciationPolicy, 0)
+	err := database.Store.Select(&policies, SQL)
+	return policies, err
+}
+
+func UpsertDepreciationPolicy(assetType string, request models.DepreciationPolicyRequest) error {
+	SQL := `INSERT INTO depreciation_policies (asset_type, method, useful_life_months, salvage_percent, declining_rate, updated_at)
+			VALUES ($1,$2,$3,$4,$5,NOW())
+			ON CONFLICT (asset_type) DO UPDATE
+			SET method=EXCLUDED.method,
+			    useful_life_months=EXCLUDED.useful_life_months,
+			    salvage_percent=EXCLUDED.salvage_percent,
+			    declining_rate=EXCLUDED.declining_rate,
+			    updated_at=NOW()
+			`
+	_, err := database.Store.Exec(SQL, assetType, request.Method, request.UsefulLifeMonths, request.SalvagePercent, request.DecliningRate)
+	return err
+}
+
+const valuedAssetSQL = `SELECT a.id, a.serial_number, a.type, a.purchase_date, a.purchase_price, a.currency,
+			       dp.method, dp.useful_life_months, dp.salvage_percent, dp.declining_rate
+			FROM assets a
+			LEFT JOIN depreciation_policies dp ON dp.asset_type = a.type
+			`
+
+func GetValuedAsset(assetID string) (models.ValuedAsset, error) {
+	SQL := valuedAssetSQL + `WHERE a.id=$1
+			AND a.archived_at IS NULL
+			`
+	var asset models.ValuedAsset
+	err := database.Store.Get(&asset, SQL, assetID)
+	return asset, err
+}
+
+// ListValuedAssets returns the assets the company held on asOf, that is bought on or before
+// that day (or with no purchase date recorded) and neither archived nor disposed of by then.
+func ListValuedAssets(asOf time.Time) ([]models.ValuedAsset, error) {
+	SQL := valuedAssetSQL + `WHERE (a.purchase_date IS NULL OR a.purchase_date <= $1)
+			AND (a.archived_at IS NULL OR a.archived_at > $1)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM asset_disposals d
+				WHERE d.asset_id=a.id
+				AND d.status='completed'
+				AND d.disposed_on <= $1
+			)
+			ORDER BY a.type, a.purchase_date
+			`
+	assets := make([]models.ValuedAsset, 0)
+	err := database.Store.Select(&assets, SQL, asOf)
+	return assets, err
+}
@@ -0,0 +1,158 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateDisposal(tx *sqlx.Tx, assetID, requestedBy string, request models.RequestDisposalRequest) (string, error) {
+	SQL := `INSERT INTO asset_disposals (asset_id, method, reason, requested_by)
+			VALUES ($1,$2,$3,$4)
+			RETURNING id
+			`
+	var disposalID string
+	err := tx.Get(&disposalID, SQL, assetID, request.Method, request.Reason, requestedBy)
+	return disposalID, err
+}
+
+// HasOpenDisposal tells whether the asset has a disposal that is not rejected or cancelled.
+func HasOpenDisposal(tx *sqlx.Tx, assetID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_disposals
+			WHERE asset_id=$1
+			AND status IN ('pending_approval', 'approved', 'completed')
+			`
+	var open bool
+	err := tx.Get(&open, SQL, assetID)
+	return open, err
+}
+
+const disposalSQL = `SELECT d.id, d.asset_id, a.serial_number, a.type, d.method, d.reason, d.requested_by,
+			       u.name AS requested_by_name, d.status, d.created_at, d.decided_by, d.decided_at,
+			       d.decision_notes, d.disposed_on, d.recipient, d.proceeds, d.currency, d.data_wiped,
+			       d.wipe_method, d.book_value, d.gain_loss, d.completed_by, d.completed_at,
+			       d.certificate_name, d.certificate_uploaded_at
+			FROM asset_disposals d
+			JOIN assets a ON a.id=d.asset_id
+			JOIN users u ON u.id=d.requested_by
+			`
+
+// ListDisposals lists disposals filtered by status and asset, each filter is skipped when empty.
+func ListDisposals(status, assetID string) ([]models.AssetDisposal, error) {
+	SQL := disposalSQL + `WHERE ($1 = '' OR d.status::text=$1)
+			AND ($2 = '' OR d.asset_id::text=$2)
+			ORDER BY d.created_at DESC
+			`
+	disposals := make([]models.AssetDisposal, 0)
+	err := database.Store.Select(&disposals, SQL, status, assetID)
+	return disposals, err
+}
+
+func GetDisposal(disposalID string) (models.AssetDisposal, error) {
+	SQL := disposalSQL + `WHERE d.id=$1`
+	var disposal models.AssetDisposal
+	err := database.Store.Get(&disposal, SQL, disposalID)
+	return disposal, err
+}
+
+func GetDisposalForUpdate(tx *sqlx.Tx, disposalID string) (models.AssetDisposal, error) {
+	SQL := disposalSQL + `WHERE d.id=$1
+			FOR UPDATE OF d
+			`
+	var disposal models.AssetDisposal
+	err := tx.Get(&disposal, SQL, disposalID)
+	return disposal, err
+}
+
+// DecideDisposal moves a disposal waiting for approval to approved or rejected.
+func DecideDisposal(tx *sqlx.Tx, disposalID, status, decidedBy, notes string) error {
+	SQL := `UPDATE asset_disposals
+			SET status=$2,
+			    decided_by=$3,
+			    decided_at=NOW(),
+			    decision_notes=NULLIF($4,'')
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, disposalID, status, decidedBy, notes)
+	return err
+}
+
+func CancelDisposal(tx *sqlx.Tx, disposalID string) error {
+	SQL := `UPDATE asset_disposals
+			SET status='cancelled'
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, disposalID)
+	return err
+}
+
+// CompleteDisposal records how an asset left, with its book value and the gain or loss on the
+// disposal date, both nil when the asset cannot be valued.
+func CompleteDisposal(tx *sqlx.Tx, disposalID, completedBy string, request models.CompleteDisposalRequest, currency *string, bookValue, gainLoss *float64) error {
+	SQL := `UPDATE asset_disposals
+			SET status='completed',
+			    disposed_on=$3,
+			    recipient=$4,
+			    proceeds=$5,
+			    currency=$6,
+			    data_wiped=$7,
+			    wipe_method=NULLIF($8,''),
+			    book_value=$9,
+			    gain_loss=$10,
+			    completed_by=$2,
+			    completed_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, disposalID, completedBy, request.DisposedOn, request.Recipient, request.Proceeds,
+		currency, request.DataWiped, request.WipeMethod, bookValue, gainLoss)
+	return err
+}
+
+// SaveDisposalCertificate stores the certificate of a disposal, replacing any earlier one.
+func SaveDisposalCertificate(disposalID, name, contentType string, content []byte) (bool, error) {
+	SQL := `UPDATE asset_disposals
+			SET certificate=$2,
+			    certificate_name=$3,
+			    certificate_content_type=$4,
+			    certificate_uploaded_at=NOW()
+			WHERE id=$1
+			AND status IN ('approved', 'completed')
+			`
+	result, err := database.Store.Exec(SQL, disposalID, content, name, contentType)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func GetDisposalCertificate(disposalID string) (models.DisposalCertificate, error) {
+	SQL := `SELECT certificate_name, certificate_content_type, certificate
+			FROM asset_disposals
+			WHERE id=$1
+			AND certificate IS NOT NULL
+			`
+	var certificate models.DisposalCertificate
+	err := database.Store.Get(&certificate, SQL, disposalID)
+	return certificate, err
+}
+
+// ListDisposedAssets lists the disposals completed in a period, each bound is skipped when nil.
+func ListDisposedAssets(from, to *time.Time) ([]models.DisposedAsset, error) {
+	SQL := `SELECT d.id, d.asset_id, a.serial_number, a.type, d.method, d.disposed_on, d.recipient,
+			       a.purchase_price, d.book_value, d.proceeds, d.currency, d.gain_loss,
+			       d.certificate IS NOT NULL AS has_certificate
+			FROM asset_disposals d
+			JOIN assets a ON a.id=d.asset_id
+			WHERE d.status='completed'
+			AND ($1::date IS NULL OR d.disposed_on>=$1)
+			AND ($2::date IS NULL OR d.disposed_on<=$2)
+			ORDER BY d.disposed_on, a.serial_number
+			`
+	assets := make([]models.DisposedAsset, 0)
+	err := database.Store.Select(&assets, SQL, from, to)
+	return assets, err
+}
@@ -0,0 +1,154 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateIncident(tx *sqlx.Tx, assetID, reportedBy string, request models.ReportIncidentRequest) (string, error) {
+	SQL := `INSERT INTO asset_incidents (asset_id, kind, reported_by, occurred_on, location, police_report_number, remarks)
+			VALUES ($1,$2,$3,$4,NULLIF($5,''),NULLIF($6,''),NULLIF($7,''))
+			RETURNING id
+			`
+	var incidentID string
+	err := tx.Get(&incidentID, SQL, assetID, request.Kind, reportedBy, request.OccurredOn,
+		request.Location, request.PoliceReportNumber, request.Remarks)
+	return incidentID, err
+}
+
+const incidentSQL = `SELECT i.id, i.asset_id, a.serial_number, i.kind, i.reported_by, u.name AS reported_by_name,
+			       i.occurred_on, i.location, i.police_report_number, i.remarks, i.status, i.recovered_by,
+			       i.recovered_at, i.recovery_condition, i.recovery_notes, i.created_at
+			FROM asset_incidents i
+			JOIN assets a ON a.id=i.asset_id
+			JOIN users u ON u.id=i.reported_by
+			`
+
+// ListIncidents lists incidents filtered by status and asset, each filter is skipped when empty.
+func ListIncidents(status, assetID string) ([]models.AssetIncident, error) {
+	SQL := incidentSQL + `WHERE ($1 = '' OR i.status::text=$1)
+			AND ($2 = '' OR i.asset_id::text=$2)
+			ORDER BY i.created_at DESC
+			`
+	incidents := make([]models.AssetIncident, 0)
+	err := database.Store.Select(&incidents, SQL, status, assetID)
+	return incidents, err
+}
+
+func GetOpenIncidentForUpdate(tx *sqlx.Tx, assetID string) (models.AssetIncident, error) {
+	SQL := incidentSQL + `WHERE i.asset_id=$1
+			AND i.status='open'
+			FOR UPDATE OF i
+			`
+	var incident models.AssetIncident
+	err := tx.Get(&incident, SQL, assetID)
+	return incident, err
+}
+
+func RecoverIncident(tx *sqlx.Tx, incidentID, recoveredBy, condition, notes string) error {
+	SQL := `UPDATE asset_incidents
+			SET status='recovered',
+			    recovered_by=$2,
+			    recovered_at=NOW(),
+			    recovery_condition=$3,
+			    recovery_notes=NULLIF($4,'')
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, incidentID, recoveredBy, condition, notes)
+	return err
+}
+
+// CreateRotationReminders adds a reminder for every secret stored for the asset.
+func CreateRotationReminders(tx *sqlx.Tx, assetID, incidentID string) error {
+	SQL := `INSERT INTO secret_rotation_reminders (asset_id, incident_id, field)
+			SELECT asset_id, $2, field
+			FROM asset_secrets
+			WHERE asset_id=$1
+			`
+	_, err := tx.Exec(SQL, assetID, incidentID)
+	return err
+}
+
+// CompleteRotationReminders closes the pending reminders of a secret once it is replaced.
+func CompleteRotationReminders(tx *sqlx.Tx, assetID, field string) error {
+	SQL := `UPDATE secret_rotation_reminders
+			SET status='done',
+			    completed_at=NOW()
+			WHERE asset_id=$1
+			AND field=$2
+			AND status='pending'
+			`
+	_, err := tx.Exec(SQL, assetID, field)
+	return err
+}
+
+func CompleteRotationReminder(reminderID, completedBy string) (bool, error) {
+	SQL := `UPDATE secret_rotation_reminders
+			SET status='done',
+			    completed_at=NOW(),
+			    completed_by=$2
+			WHERE id=$1
+			AND status='pending'
+			`
+	result, err := database.Store.Exec(SQL, reminderID, completedBy)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+const rotationReminderSQL = `SELECT r.id, r.asset_id, a.serial_number, r.incident_id, i.kind AS incident_kind, r.field,
+			       r.status, r.created_at, r.last_reminded_at, r.completed_at, r.completed_by
+			FROM secret_rotation_reminders r
+			JOIN assets a ON a.id=r.asset_id
+			JOIN asset_incidents i ON i.id=r.incident_id
+			`
+
+// ListRotationReminders lists reminders filtered by status, skipped when empty.
+func ListRotationReminders(status string) ([]models.RotationReminder, error) {
+	SQL := rotationReminderSQL + `WHERE ($1 = '' OR r.status::text=$1)
+			ORDER BY r.created_at
+			`
+	reminders := make([]models.RotationReminder, 0)
+	err := database.Store.Select(&reminders, SQL, status)
+	return reminders, err
+}
+
+// ListDueRotationReminders lists the pending reminders not sent since the given time.
+func ListDueRotationReminders(remindedBefore time.Time) ([]models.RotationReminder, error) {
+	SQL := rotationReminderSQL + `WHERE r.status='pending'
+			AND (r.last_reminded_at IS NULL OR r.last_reminded_at<$1)
+			ORDER BY r.created_at
+			`
+	reminders := make([]models.RotationReminder, 0)
+	err := database.Store.Select(&reminders, SQL, remindedBefore)
+	return reminders, err
+}
+
+func MarkRemindersSent(reminderIDs []string) error {
+	SQL := `UPDATE secret_rotation_reminders
+			SET last_reminded_at=NOW()
+			WHERE id=ANY($1::uuid[])
+			`
+	_, err := database.Store.Exec(SQL, pq.StringArray(reminderIDs))
+	return err
+}
+
+// ListEmailsWithPermission returns the email of every active user whose role has the permission.
+func ListEmailsWithPermission(permission string) ([]string, error) {
+	SQL := `SELECT u.email
+			FROM users u
+			JOIN role_permissions rp ON rp.role=u.role
+			WHERE rp.permission=$1
+			AND u.archived_at IS NULL
+			ORDER BY u.email
+			`
+	emails := make([]string, 0)
+	err := database.Store.Select(&emails, SQL, permission)
+	return emails, err
+}
@@ -0,0 +1,146 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+const kitTemplateSQL = `SELECT k.id, k.name, k.role, k.employment_type, k.created_at, k.updated_at,
+			       COALESCE(JSON_AGG(JSON_BUILD_OBJECT('assetType', i.asset_type, 'quantity', i.quantity)
+			                ORDER BY i.asset_type) FILTER (WHERE i.asset_type IS NOT NULL), '[]') AS items
+			FROM kit_templates k
+			LEFT JOIN kit_template_items i ON i.template_id=k.id
+			WHERE k.archived_at IS NULL
+			`
+
+func ListKitTemplates() ([]models.KitTemplate, error) {
+	SQL := kitTemplateSQL + `GROUP BY k.id
+			ORDER BY k.name
+			`
+	templates := make([]models.KitTemplate, 0)
+	err := database.Store.Select(&templates, SQL)
+	return templates, err
+}
+
+func GetKitTemplate(templateID string) (models.KitTemplate, error) {
+	SQL := kitTemplateSQL + `AND k.id=$1
+			GROUP BY k.id
+			`
+	var template models.KitTemplate
+	err := database.Store.Get(&template, SQL, templateID)
+	return template, err
+}
+
+// MatchKitTemplate returns the template for a role and employment type. Templates naming the role
+// win over templates naming the employment type, which win over templates naming neither.
+func MatchKitTemplate(role, employmentType string) (string, error) {
+	SQL := `SELECT id
+			FROM kit_templates
+			WHERE archived_at IS NULL
+			AND (role IS NULL OR role=$1)
+			AND (employment_type IS NULL OR employment_type::text=$2)
+			ORDER BY role IS NULL, employment_type IS NULL, created_at
+			LIMIT 1
+			`
+	var templateID string
+	err := database.Store.Get(&templateID, SQL, role, employmentType)
+	return templateID, err
+}
+
+func IsKitTemplateNameTaken(name, exceptID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM kit_templates
+			WHERE name=$1
+			AND id::text<>$2
+			AND archived_at IS NULL
+			`
+	var taken bool
+	err := database.Store.Get(&taken, SQL, name, exceptID)
+	return taken, err
+}
+
+func CreateKitTemplate(tx *sqlx.Tx, request models.KitTemplateRequest) (string, error) {
+	SQL := `INSERT INTO kit_templates (name, role, employment_type)
+			VALUES ($1,NULLIF($2,''),NULLIF($3,'')::user_type)
+			RETURNING id
+			`
+	var templateID string
+	err := tx.Get(&templateID, SQL, request.Name, request.Role, request.EmploymentType)
+	return templateID, err
+}
+
+func UpdateKitTemplate(tx *sqlx.Tx, templateID string, request models.KitTemplateRequest) (bool, error) {
+	SQL := `UPDATE kit_templates
+			SET name=$2,
+			    role=NULLIF($3,''),
+			    employment_type=NULLIF($4,'')::user_type,
+			    updated_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := tx.Exec(SQL, templateID, request.Name, request.Role, request.EmploymentType)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func ReplaceKitItems(tx *sqlx.Tx, templateID string, items []models.KitItem) error {
+	if _, err := tx.Exec(`DELETE FROM kit_template_items WHERE template_id=$1`, templateID); err != nil {
+		return err
+	}
+	SQL := `INSERT INTO kit_template_items (template_id, asset_type, quantity)
+			VALUES ($1,$2,$3)
+			`
+	for _, item := range items {
+		if _, err := tx.Exec(SQL, templateID, item.AssetType, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ArchiveKitTemplate(templateID string) (bool, error) {
+	SQL := `UPDATE kit_templates
+			SET archived_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, templateID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func ListKitItems(templateID string) ([]models.KitItem, error) {
+	SQL := `SELECT asset_type, quantity
+			FROM kit_template_items
+			WHERE template_id=$1
+			ORDER BY asset_type
+			`
+	items := make([]models.KitItem, 0)
+	err := database.Store.Select(&items, SQL, templateID)
+	return items, err
+}
+
+// PickAvailableAssets locks up to count available assets of a type, oldest first. Assets locked
+// by concurrent transactions are skipped rather than waited for, so two kits being assigned at
+// once never pick the same asset.
+func PickAvailableAssets(tx *sqlx.Tx, assetType string, count int) ([]models.KitAsset, error) {
+	SQL := `SELECT id, type, serial_number
+			FROM assets
+			WHERE type=$1
+			AND status='available'
+			AND archived_at IS NULL
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+			`
+	assets := make([]models.KitAsset, 0)
+	err := tx.Select(&assets, SQL, assetType, count)
+	return assets, err
+}
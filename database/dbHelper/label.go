@@ -0,0 +1,60 @@
+package dbHelper
+
+import (
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+// ListAssetLabels lists the labels of the assets matching the filter, at most limit of them.
+// Disposed assets are left out unless asked for by status.
+func ListAssetLabels(filter models.LabelFilter, limit int) ([]models.AssetLabel, error) {
+	SQL := `SELECT id, asset_tag, serial_number, brand, model
+			FROM assets
+			WHERE archived_at IS NULL
+			AND (cardinality($1::uuid[]) = 0 OR id=ANY($1::uuid[]))
+			AND ($2 = '' OR type::text=$2)
+			AND ($3 = '' OR status::text=$3)
+			AND ($3 <> '' OR status<>'disposed')
+			AND ($4 = '' OR owner::text=$4)
+			AND ($5 = '' OR location_id IN (SELECT location_subtree($5)))
+			ORDER BY asset_tag
+			LIMIT $6
+			`
+	labels := make([]models.AssetLabel, 0)
+	err := database.Store.Select(&labels, SQL, pq.StringArray(filter.AssetIDs), filter.Type, filter.Status,
+		filter.Owner, filter.LocationID, limit)
+	return labels, err
+}
+
+func GetAssetLabel(assetID string) (models.AssetLabel, error) {
+	SQL := `SELECT id, asset_tag, serial_number, brand, model
+			FROM assets
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	var label models.AssetLabel
+	err := database.Store.Get(&label, SQL, assetID)
+	return label, err
+}
+
+// GetAssetByCode finds the asset a scanned code belongs to, trying the asset tag first and the
+// serial number second. Archived assets are found too, so that a stray label can be traced.
+// Tags are generated in upper case, so the code is upper cased rather than the column, keeping
+// the unique index on asset_tag usable.
+func GetAssetByCode(code string) (models.AssetDetail, error) {
+	SQL := `SELECT a.id, a.asset_tag, a.serial_number, a.brand, a.model, a.type, a.status, a.owner,
+			       a.assigned_to, u.name AS assigned_to_name, a.assigned_on, a.location_id,
+			       a.warranty_start, a.warranty_end, a.purchase_date, a.specs, a.custom_fields,
+			       a.created_at, a.archived_at
+			FROM assets a
+			LEFT JOIN users u ON u.id=a.assigned_to
+			WHERE a.asset_tag=upper($1)
+			OR a.serial_number=$1
+			ORDER BY a.asset_tag=upper($1) DESC
+			LIMIT 1
+			`
+	var asset models.AssetDetail
+	err := database.Store.Get(&asset, SQL, code)
+	return asset, err
+}
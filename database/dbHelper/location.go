@@ -0,0 +1,201 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+const locationSQL = `WITH RECURSIVE tree AS (
+				SELECT id, name::text AS path
+				FROM locations
+				WHERE parent_id IS NULL
+				AND archived_at IS NULL
+				UNION ALL
+				SELECT l.id, t.path || ' / ' || l.name
+				FROM locations l
+				JOIN tree t ON t.id=l.parent_id
+				WHERE l.archived_at IS NULL
+			)
+			SELECT l.id, l.name, l.kind, l.parent_id, l.address, t.path, l.created_at,
+			       (SELECT count(*) FROM assets a
+			        WHERE a.location_id=l.id AND a.archived_at IS NULL AND a.status<>'disposed') AS assets
+			FROM locations l
+			JOIN tree t ON t.id=l.id
+			`
+
+// ListLocations lists the locations below the given one, itself included, or every location
+// when it is empty.
+func ListLocations(rootID string) ([]models.Location, error) {
+	SQL := locationSQL + `WHERE ($1 = '' OR l.id IN (SELECT location_subtree($1)))
+			ORDER BY t.path
+			`
+	locations := make([]models.Location, 0)
+	err := database.Store.Select(&locations, SQL, rootID)
+	return locations, err
+}
+
+func GetLocation(locationID string) (models.Location, error) {
+	SQL := locationSQL + `WHERE l.id=$1`
+	var location models.Location
+	err := database.Store.Get(&location, SQL, locationID)
+	return location, err
+}
+
+// IsLocationNameTaken tells whether another active location under the same parent, or another
+// site when the parent is empty, already has the name.
+func IsLocationNameTaken(name, parentID, exceptID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM locations
+			WHERE lower(name)=lower($1)
+			AND COALESCE(parent_id::text,'')=$2
+			AND id::text<>$3
+			AND archived_at IS NULL
+			`
+	var taken bool
+	err := database.Store.Get(&taken, SQL, name, parentID, exceptID)
+	return taken, err
+}
+
+func CreateLocation(request models.CreateLocationRequest) (string, error) {
+	SQL := `INSERT INTO locations (name, kind, parent_id, address)
+			VALUES ($1,$2,NULLIF($3,'')::uuid,NULLIF($4,''))
+			RETURNING id
+			`
+	var locationID string
+	err := database.Store.Get(&locationID, SQL, request.Name, request.Kind, request.ParentID, request.Address)
+	return locationID, err
+}
+
+func UpdateLocation(locationID string, request models.UpdateLocationRequest) error {
+	SQL := `UPDATE locations
+			SET name=$2,
+			    address=NULLIF($3,'')
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := database.Store.Exec(SQL, locationID, request.Name, request.Address)
+	return err
+}
+
+// IsLocationInUse tells whether a location still holds assets or has locations below it.
+func IsLocationInUse(tx *sqlx.Tx, locationID string) (bool, error) {
+	SQL := `SELECT EXISTS (
+				SELECT 1 FROM assets
+				WHERE location_id=$1
+				AND archived_at IS NULL
+				AND status<>'disposed'
+			) OR EXISTS (
+				SELECT 1 FROM locations
+				WHERE parent_id=$1
+				AND archived_at IS NULL
+			)
+			`
+	var inUse bool
+	err := tx.Get(&inUse, SQL, locationID)
+	return inUse, err
+}
+
+// GetLocationKindForUpdate locks an active location for the rest of the transaction and returns its kind.
+func GetLocationKindForUpdate(tx *sqlx.Tx, locationID string) (string, error) {
+	SQL := `SELECT kind
+			FROM locations
+			WHERE id=$1
+			AND archived_at IS NULL
+			FOR UPDATE
+			`
+	var kind string
+	err := tx.Get(&kind, SQL, locationID)
+	return kind, err
+}
+
+func ArchiveLocation(tx *sqlx.Tx, locationID string) error {
+	SQL := `UPDATE locations
+			SET archived_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, locationID)
+	return err
+}
+
+func GetAssetLocation(tx *sqlx.Tx, assetID string) (*string, error) {
+	SQL := `SELECT location_id
+			FROM assets
+			WHERE id=$1
+			`
+	var locationID *string
+	err := tx.Get(&locationID, SQL, assetID)
+	return locationID, err
+}
+
+// MoveAsset sets the location of an asset and records the movement, with the sites at both ends.
+func MoveAsset(tx *sqlx.Tx, assetID string, from, to *string, reason, movedBy, notes string) error {
+	SQL := `UPDATE assets
+			SET location_id=$2,
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	if _, err := tx.Exec(SQL, assetID, to); err != nil {
+		return err
+	}
+	SQL = `INSERT INTO asset_movements (asset_id, from_location, to_location, from_site, to_site, reason, moved_by, notes)
+			VALUES ($1,$2::uuid,$3::uuid,location_site($2::uuid),location_site($3::uuid),$4,$5,NULLIF($6,''))
+			`
+	_, err := tx.Exec(SQL, assetID, from, to, reason, movedBy, notes)
+	return err
+}
+
+// LastLocationBeforeAssignment returns where the asset was kept when it was last assigned.
+func LastLocationBeforeAssignment(tx *sqlx.Tx, assetID string) (*string, error) {
+	SQL := `SELECT from_location
+			FROM asset_movements
+			WHERE asset_id=$1
+			AND reason='assigned'
+			ORDER BY moved_at DESC
+			LIMIT 1
+			`
+	locationIDs := make([]*string, 0)
+	if err := tx.Select(&locationIDs, SQL, assetID); err != nil {
+		return nil, err
+	}
+	if len(locationIDs) == 0 {
+		return nil, nil
+	}
+	return locationIDs[0], nil
+}
+
+const movementSQL = `SELECT m.id, m.asset_id, a.serial_number, m.from_location, fl.name AS from_location_name,
+			       m.to_location, tl.name AS to_location_name, m.from_site, m.to_site, m.reason,
+			       m.moved_by, m.moved_at, m.notes
+			FROM asset_movements m
+			JOIN assets a ON a.id=m.asset_id
+			LEFT JOIN locations fl ON fl.id=m.from_location
+			LEFT JOIN locations tl ON tl.id=m.to_location
+			`
+
+func ListAssetMovements(assetID string) ([]models.AssetMovement, error) {
+	SQL := movementSQL + `WHERE m.asset_id=$1
+			ORDER BY m.moved_at DESC
+			`
+	movements := make([]models.AssetMovement, 0)
+	err := database.Store.Select(&movements, SQL, assetID)
+	return movements, err
+}
+
+// ListMovements lists movements in a period touching a location or anything below it. Each
+// filter is skipped when empty; crossSite keeps only the movements from one site to another.
+func ListMovements(from, to *time.Time, locationID string, crossSite bool) ([]models.AssetMovement, error) {
+	SQL := movementSQL + `WHERE ($1::timestamptz IS NULL OR m.moved_at >= $1)
+			AND ($2::timestamptz IS NULL OR m.moved_at <= $2)
+			AND ($3 = ''
+			    OR m.from_location IN (SELECT location_subtree($3))
+			    OR m.to_location IN (SELECT location_subtree($3)))
+			AND (NOT $4 OR m.from_site <> m.to_site)
+			ORDER BY m.moved_at DESC
+			`
+	movements := make([]models.AssetMovement, 0)
+	err := database.Store.Select(&movements, SQL, from, to, locationID, crossSite)
+	return movements, err
+}
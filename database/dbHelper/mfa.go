@@ -0,0 +1,164 @@
+package dbHelper
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+// SavePendingMFA stores a new unconfirmed TOTP secret, replacing an earlier unconfirmed one. It
+// returns false when the user already has MFA enabled.
+func SavePendingMFA(userID string, ciphertext, wrappedKey []byte, keyID string) (bool, error) {
+	SQL := `INSERT INTO user_mfa (user_id, ciphertext, wrapped_key, key_id)
+			VALUES ($1,$2,$3,$4)
+			ON CONFLICT (user_id) DO UPDATE
+			SET ciphertext=EXCLUDED.ciphertext,
+			    wrapped_key=EXCLUDED.wrapped_key,
+			    key_id=EXCLUDED.key_id,
+			    last_used_step=0,
+			    created_at=NOW()
+			WHERE user_mfa.confirmed_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, userID, ciphertext, wrappedKey, keyID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func GetUserMFAForUpdate(tx *sqlx.Tx, userID string) (models.UserMFA, error) {
+	SQL := `SELECT user_id, ciphertext, wrapped_key, key_id, last_used_step, confirmed_at
+			FROM user_mfa
+			WHERE user_id=$1
+			FOR UPDATE
+			`
+	var mfa models.UserMFA
+	err := tx.Get(&mfa, SQL, userID)
+	return mfa, err
+}
+
+// IsMFAEnabled reports whether the user has a confirmed TOTP secret.
+func IsMFAEnabled(userID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM user_mfa
+			WHERE user_id=$1
+			AND confirmed_at IS NOT NULL
+			`
+	var enabled bool
+	err := database.Store.Get(&enabled, SQL, userID)
+	return enabled, err
+}
+
+func ConfirmUserMFA(tx *sqlx.Tx, userID string, step int64) error {
+	SQL := `UPDATE user_mfa
+			SET confirmed_at=NOW(),
+			    last_used_step=$2
+			WHERE user_id=$1
+			`
+	_, err := tx.Exec(SQL, userID, step)
+	return err
+}
+
+func UpdateMFALastUsedStep(tx *sqlx.Tx, userID string, step int64) error {
+	SQL := `UPDATE user_mfa
+			SET last_used_step=$2
+			WHERE user_id=$1
+			`
+	_, err := tx.Exec(SQL, userID, step)
+	return err
+}
+
+func DeleteUserMFA(tx *sqlx.Tx, userID string) error {
+	if _, err := tx.Exec(`DELETE FROM mfa_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM user_mfa WHERE user_id=$1`, userID)
+	return err
+}
+
+// ReplaceRecoveryCodes drops the unused recovery codes of the user and stores the new ones.
+func ReplaceRecoveryCodes(tx *sqlx.Tx, userID string, codeHashes []string) error {
+	SQL := `DELETE FROM mfa_recovery_codes
+			WHERE user_id=$1
+			AND used_at IS NULL
+			`
+	if _, err := tx.Exec(SQL, userID); err != nil {
+		return err
+	}
+	SQL = `INSERT INTO mfa_recovery_codes (user_id, code_hash)
+			SELECT $1, UNNEST($2::text[])
+			`
+	_, err := tx.Exec(SQL, userID, pq.StringArray(codeHashes))
+	return err
+}
+
+// UseRecoveryCode redeems an unused recovery code, returning false when none matches.
+func UseRecoveryCode(tx *sqlx.Tx, userID, codeHash string) (bool, error) {
+	SQL := `UPDATE mfa_recovery_codes
+			SET used_at=NOW()
+			WHERE id = (
+				SELECT id
+				FROM mfa_recovery_codes
+				WHERE user_id=$1
+				AND code_hash=$2
+				AND used_at IS NULL
+				LIMIT 1
+			)
+			`
+	result, err := tx.Exec(SQL, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func MarkSessionMFAVerified(tx *sqlx.Tx, sessionID string) error {
+	SQL := `UPDATE user_session
+			SET mfa_verified=TRUE
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, sessionID)
+	return err
+}
+
+// IsMFARequired reports whether the role requires sessions to pass MFA.
+func IsMFARequired(role string) (bool, error) {
+	SQL := `SELECT mfa_required
+			FROM roles
+			WHERE name=$1
+			`
+	var required bool
+	err := database.Store.Get(&required, SQL, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return required, err
+}
+
+// ListMFASecretsToRewrap locks the TOTP secrets whose data keys are not wrapped by the active master key.
+func ListMFASecretsToRewrap(tx *sqlx.Tx, activeKeyID string) ([]models.UserMFA, error) {
+	SQL := `SELECT user_id, ciphertext, wrapped_key, key_id, last_used_step, confirmed_at
+			FROM user_mfa
+			WHERE key_id<>$1
+			FOR UPDATE
+			`
+	secrets := make([]models.UserMFA, 0)
+	err := tx.Select(&secrets, SQL, activeKeyID)
+	return secrets, err
+}
+
+func RewrapMFASecret(tx *sqlx.Tx, userID string, wrappedKey []byte, keyID string) error {
+	SQL := `UPDATE user_mfa
+			SET wrapped_key=$2,
+			    key_id=$3
+			WHERE user_id=$1
+			`
+	_, err := tx.Exec(SQL, userID, wrappedKey, keyID)
+	return err
+}
@@ -0,0 +1,162 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateOffboarding(tx *sqlx.Tx, userID, startedBy string, request models.StartOffboardingRequest) (string, error) {
+	SQL := `INSERT INTO offboardings (user_id, started_by, last_working_day, notes)
+			VALUES ($1,$2,$3,NULLIF($4,''))
+			RETURNING id
+			`
+	var offboardingID string
+	err := tx.Get(&offboardingID, SQL, userID, startedBy, request.LastWorkingDay, request.Notes)
+	return offboardingID, err
+}
+
+// CreateReturnTasks adds a return task for every asset the user holds and returns how many were added.
+func CreateReturnTasks(tx *sqlx.Tx, offboardingID, userID string) (int64, error) {
+	SQL := `INSERT INTO offboarding_return_tasks (offboarding_id, asset_id, assignment_id)
+			SELECT $1, aa.asset_id, aa.id
+			FROM asset_assignments aa
+			WHERE aa.assigned_to=$2
+			AND aa.assigned_until IS NULL
+			`
+	result, err := tx.Exec(SQL, offboardingID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func IsOffboarding(tx *sqlx.Tx, userID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM offboardings
+			WHERE user_id=$1
+			AND status='open'
+			`
+	var offboarding bool
+	err := tx.Get(&offboarding, SQL, userID)
+	return offboarding, err
+}
+
+// CountHeldAssets counts the assets currently assigned to a user.
+func CountHeldAssets(tx *sqlx.Tx, userID string) (int, error) {
+	SQL := `SELECT count(*)
+			FROM asset_assignments
+			WHERE assigned_to=$1
+			AND assigned_until IS NULL
+			`
+	var held int
+	err := tx.Get(&held, SQL, userID)
+	return held, err
+}
+
+// ResolveReturnTask resolves the pending return task of an asset, if there is one, and returns
+// the offboarding it belongs to.
+func ResolveReturnTask(tx *sqlx.Tx, assetID, resolvedBy, status, notes string) (string, error) {
+	SQL := `UPDATE offboarding_return_tasks
+			SET status=$3,
+			    resolved_by=$2,
+			    resolved_at=NOW(),
+			    notes=NULLIF($4,'')
+			WHERE asset_id=$1
+			AND status='pending'
+			RETURNING offboarding_id
+			`
+	offboardingIDs := make([]string, 0)
+	if err := tx.Select(&offboardingIDs, SQL, assetID, resolvedBy, status, notes); err != nil {
+		return "", err
+	}
+	if len(offboardingIDs) == 0 {
+		return "", nil
+	}
+	return offboardingIDs[0], nil
+}
+
+// ClearOffboardingIfDone marks an open offboarding cleared once none of its tasks is pending.
+func ClearOffboardingIfDone(tx *sqlx.Tx, offboardingID string) error {
+	SQL := `UPDATE offboardings
+			SET status='cleared',
+			    cleared_at=NOW()
+			WHERE id=$1
+			AND status='open'
+			AND NOT EXISTS (
+				SELECT 1
+				FROM offboarding_return_tasks
+				WHERE offboarding_id=$1
+				AND status='pending'
+			)
+			`
+	_, err := tx.Exec(SQL, offboardingID)
+	return err
+}
+
+const offboardingSQL = `SELECT o.id, o.user_id, u.name AS user_name, u.email AS user_email, o.started_by,
+			       o.last_working_day, o.notes, o.status, o.started_at, o.cleared_at,
+			       (SELECT count(*) FROM offboarding_return_tasks t
+			        WHERE t.offboarding_id=o.id AND t.status='pending') AS pending_tasks
+			FROM offboardings o
+			JOIN users u ON u.id=o.user_id
+			`
+
+// ListOffboardings lists offboardings filtered by status, skipped when empty.
+func ListOffboardings(status string) ([]models.Offboarding, error) {
+	SQL := offboardingSQL + `WHERE ($1 = '' OR o.status::text=$1)
+			ORDER BY o.started_at DESC
+			`
+	offboardings := make([]models.Offboarding, 0)
+	err := database.Store.Select(&offboardings, SQL, status)
+	return offboardings, err
+}
+
+func GetOffboarding(offboardingID string) (models.Offboarding, error) {
+	SQL := offboardingSQL + `WHERE o.id=$1`
+	var offboarding models.Offboarding
+	err := database.Store.Get(&offboarding, SQL, offboardingID)
+	return offboarding, err
+}
+
+const returnTaskSQL = `SELECT t.id, t.offboarding_id, t.asset_id, a.serial_number, a.brand, a.model, a.type,
+			       aa.assigned_from, t.status, aa.return_condition, t.resolved_by,
+			       r.name AS resolved_by_name, t.resolved_at, t.notes
+			FROM offboarding_return_tasks t
+			JOIN assets a ON a.id=t.asset_id
+			JOIN asset_assignments aa ON aa.id=t.assignment_id
+			LEFT JOIN users r ON r.id=t.resolved_by
+			`
+
+func ListReturnTasks(offboardingID string) ([]models.ReturnTask, error) {
+	SQL := returnTaskSQL + `WHERE t.offboarding_id=$1
+			ORDER BY a.serial_number
+			`
+	tasks := make([]models.ReturnTask, 0)
+	err := database.Store.Select(&tasks, SQL, offboardingID)
+	return tasks, err
+}
+
+func GetReturnTaskForUpdate(tx *sqlx.Tx, offboardingID, taskID string) (models.ReturnTask, error) {
+	SQL := returnTaskSQL + `WHERE t.offboarding_id=$1
+			AND t.id=$2
+			FOR UPDATE OF t
+			`
+	var task models.ReturnTask
+	err := tx.Get(&task, SQL, offboardingID, taskID)
+	return task, err
+}
+
+// WriteOffAsset takes an asset off its holder, if any, and marks it lost or stolen.
+func WriteOffAsset(tx *sqlx.Tx, assetID, status string) error {
+	SQL := `UPDATE assets
+			SET assigned_to=NULL,
+			    assigned_on=NULL,
+			    status=$2,
+			    updated_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, assetID, status)
+	return err
+}
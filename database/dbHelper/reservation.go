@@ -0,0 +1,125 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateReservation(tx *sqlx.Tx, assetID, reservedBy string, request models.ReserveAssetRequest, expiresAt time.Time) (string, error) {
+	SQL := `INSERT INTO asset_reservations (asset_id, reserved_for, reserved_by, starts_on, expires_at, notes)
+			VALUES ($1,$2,$3,$4,$5,NULLIF($6,''))
+			RETURNING id
+			`
+	var reservationID string
+	err := tx.Get(&reservationID, SQL, assetID, request.UserID, reservedBy, request.StartsOn, expiresAt, request.Notes)
+	return reservationID, err
+}
+
+const reservationSQL = `SELECT r.id, r.asset_id, a.serial_number, a.type, r.reserved_for, u.name AS reserved_for_name,
+			       r.reserved_by, r.starts_on, r.expires_at, r.notes, r.status, r.created_at, r.closed_at, r.closed_by
+			FROM asset_reservations r
+			JOIN assets a ON a.id=r.asset_id
+			JOIN users u ON u.id=r.reserved_for
+			`
+
+// ListReservations lists reservations filtered by status and intended user, each filter is skipped when empty.
+func ListReservations(status, reservedFor string) ([]models.Reservation, error) {
+	SQL := reservationSQL + `WHERE ($1 = '' OR r.status::text=$1)
+			AND ($2 = '' OR r.reserved_for::text=$2)
+			ORDER BY r.starts_on, r.created_at
+			`
+	reservations := make([]models.Reservation, 0)
+	err := database.Store.Select(&reservations, SQL, status, reservedFor)
+	return reservations, err
+}
+
+func GetReservationForUpdate(tx *sqlx.Tx, reservationID string) (models.Reservation, error) {
+	SQL := reservationSQL + `WHERE r.id=$1
+			FOR UPDATE OF r
+			`
+	var reservation models.Reservation
+	err := tx.Get(&reservation, SQL, reservationID)
+	return reservation, err
+}
+
+func GetActiveReservationForUpdate(tx *sqlx.Tx, assetID string) (models.Reservation, error) {
+	SQL := reservationSQL + `WHERE r.asset_id=$1
+			AND r.status='active'
+			FOR UPDATE OF r
+			`
+	var reservation models.Reservation
+	err := tx.Get(&reservation, SQL, assetID)
+	return reservation, err
+}
+
+// CloseReservation moves an active reservation to converted or cancelled.
+func CloseReservation(tx *sqlx.Tx, reservationID, status, closedBy string) error {
+	SQL := `UPDATE asset_reservations
+			SET status=$2,
+			    closed_at=NOW(),
+			    closed_by=NULLIF($3,'')::uuid
+			WHERE id=$1
+			AND status='active'
+			`
+	_, err := tx.Exec(SQL, reservationID, status, closedBy)
+	return err
+}
+
+// ListDueReservations lists the active reservations starting on or before the day, which have
+// not expired yet.
+func ListDueReservations(now time.Time) ([]models.Reservation, error) {
+	SQL := reservationSQL + `WHERE r.status='active'
+			AND r.starts_on<=$1::date
+			AND r.expires_at>$1
+			ORDER BY r.starts_on
+			`
+	reservations := make([]models.Reservation, 0)
+	err := database.Store.Select(&reservations, SQL, now)
+	return reservations, err
+}
+
+// ExpireReservations expires the active reservations past their expiry and returns their assets.
+func ExpireReservations(tx *sqlx.Tx, now time.Time) ([]string, error) {
+	SQL := `UPDATE asset_reservations
+			SET status='expired',
+			    closed_at=NOW()
+			WHERE status='active'
+			AND expires_at<=$1
+			RETURNING asset_id
+			`
+	assetIDs := make([]string, 0)
+	err := tx.Select(&assetIDs, SQL, now)
+	return assetIDs, err
+}
+
+// ReleaseReservedAssets puts reserved assets back in the available pool.
+func ReleaseReservedAssets(tx *sqlx.Tx, assetIDs []string) error {
+	SQL := `UPDATE assets
+			SET status='available',
+			    updated_at=NOW()
+			WHERE id=ANY($1::uuid[])
+			AND status='reserved'
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, pq.StringArray(assetIDs))
+	return err
+}
+
+// CancelUserReservations cancels the active reservations for a user and returns their assets.
+func CancelUserReservations(tx *sqlx.Tx, userID, closedBy string) ([]string, error) {
+	SQL := `UPDATE asset_reservations
+			SET status='cancelled',
+			    closed_at=NOW(),
+			    closed_by=$2
+			WHERE reserved_for=$1
+			AND status='active'
+			RETURNING asset_id
+			`
+	assetIDs := make([]string, 0)
+	err := tx.Select(&assetIDs, SQL, userID, closedBy)
+	return assetIDs, err
+}
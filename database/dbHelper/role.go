@@ -0,0 +1,129 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+// GetSessionPermissions returns the role and permissions of the user a session belongs to.
+func GetSessionPermissions(sessionID string) (models.SessionPermissions, error) {
+	SQL := `SELECT u.role, r.mfa_required, u.email_verified_at IS NOT NULL AS email_verified,
+			       COALESCE(ARRAY_AGG(rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions
+			FROM user_session s
+			JOIN users u ON u.id=s.user_id
+			JOIN roles r ON r.name=u.role
+			LEFT JOIN role_permissions rp ON rp.role=r.name
+			WHERE s.id=$1
+			GROUP BY u.role, r.mfa_required, u.email_verified_at
+			`
+	var permissions models.SessionPermissions
+	err := database.Store.Get(&permissions, SQL, sessionID)
+	return permissions, err
+}
+
+func ListPermissions() ([]models.Permission, error) {
+	SQL := `SELECT name, description
+			FROM permissions
+			ORDER BY name
+			`
+	permissions := make([]models.Permission, 0)
+	err := database.Store.Select(&permissions, SQL)
+	return permissions, err
+}
+
+// UnknownPermissions returns the given names that are not permissions.
+func UnknownPermissions(names []string) ([]string, error) {
+	SQL := `SELECT n.name
+			FROM UNNEST($1::text[]) AS n(name)
+			WHERE NOT EXISTS (SELECT 1 FROM permissions p WHERE p.name=n.name)
+			`
+	unknown := make([]string, 0)
+	err := database.Store.Select(&unknown, SQL, pq.StringArray(names))
+	return unknown, err
+}
+
+const roleColumns = `r.name, r.label, r.mfa_required, r.builtin, r.created_at, r.updated_at,
+			       COALESCE(ARRAY_AGG(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions`
+
+func ListRoles() ([]models.Role, error) {
+	SQL := `SELECT ` + roleColumns + `
+			FROM roles r
+			LEFT JOIN role_permissions rp ON rp.role=r.name
+			GROUP BY r.name
+			ORDER BY r.name
+			`
+	roles := make([]models.Role, 0)
+	err := database.Store.Select(&roles, SQL)
+	return roles, err
+}
+
+func GetRole(name string) (models.Role, error) {
+	SQL := `SELECT ` + roleColumns + `
+			FROM roles r
+			LEFT JOIN role_permissions rp ON rp.role=r.name
+			WHERE r.name=$1
+			GROUP BY r.name
+			`
+	var role models.Role
+	err := database.Store.Get(&role, SQL, name)
+	return role, err
+}
+
+func IsRoleExist(name string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM roles
+			WHERE name=$1
+			`
+	var exists bool
+	err := database.Store.Get(&exists, SQL, name)
+	return exists, err
+}
+
+func CreateRole(tx *sqlx.Tx, name, label string, mfaRequired bool) error {
+	SQL := `INSERT INTO roles (name, label, mfa_required)
+			VALUES ($1,$2,$3)
+			`
+	_, err := tx.Exec(SQL, name, label, mfaRequired)
+	return err
+}
+
+func UpdateRole(tx *sqlx.Tx, name, label string, mfaRequired bool) error {
+	SQL := `UPDATE roles
+			SET label=$2,
+			    mfa_required=$3,
+			    updated_at=NOW()
+			WHERE name=$1
+			`
+	_, err := tx.Exec(SQL, name, label, mfaRequired)
+	return err
+}
+
+// SetRolePermissions replaces the permissions of a role.
+func SetRolePermissions(tx *sqlx.Tx, role string, permissions []string) error {
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role=$1`, role); err != nil {
+		return err
+	}
+	SQL := `INSERT INTO role_permissions (role, permission)
+			SELECT $1, UNNEST($2::text[])
+			ON CONFLICT DO NOTHING
+			`
+	_, err := tx.Exec(SQL, role, pq.StringArray(permissions))
+	return err
+}
+
+func CountUsersWithRole(role string) (int, error) {
+	SQL := `SELECT count(*)
+			FROM users
+			WHERE role=$1
+			`
+	var count int
+	err := database.Store.Get(&count, SQL, role)
+	return count, err
+}
+
+func DeleteRole(name string) error {
+	_, err := database.Store.Exec(`DELETE FROM roles WHERE name=$1 AND NOT builtin`, name)
+	return err
+}
@@ -0,0 +1,120 @@
+package dbHelper
+
+import (
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func UpsertAssetSecret(tx *sqlx.Tx, assetID, field string, ciphertext, wrappedKey []byte, keyID string) error {
+	SQL := `INSERT INTO asset_secrets (asset_id, field, ciphertext, wrapped_key, key_id)
+			VALUES ($1,$2,$3,$4,$5)
+			ON CONFLICT (asset_id, field) DO UPDATE
+			SET ciphertext=EXCLUDED.ciphertext,
+			    wrapped_key=EXCLUDED.wrapped_key,
+			    key_id=EXCLUDED.key_id,
+			    updated_at=NOW()
+			`
+	_, err := tx.Exec(SQL, assetID, field, ciphertext, wrappedKey, keyID)
+	return err
+}
+
+// DeleteAssetSecretsExcept drops the secrets of fields the asset type no longer has.
+func DeleteAssetSecretsExcept(tx *sqlx.Tx, assetID string, fields []string) error {
+	SQL := `DELETE FROM asset_secrets
+			WHERE asset_id=$1
+			AND NOT (field = ANY($2))
+			`
+	_, err := tx.Exec(SQL, assetID, pq.StringArray(fields))
+	return err
+}
+
+func GetAssetSecret(assetID, field string) (models.AssetSecret, error) {
+	SQL := `SELECT id, asset_id, field, ciphertext, wrapped_key, key_id
+			FROM asset_secrets
+			WHERE asset_id=$1
+			AND field=$2
+			`
+	var secret models.AssetSecret
+	err := database.Store.Get(&secret, SQL, assetID, field)
+	return secret, err
+}
+
+// ListSecretsToRewrap locks the secrets whose data keys are not wrapped by the active master key.
+func ListSecretsToRewrap(tx *sqlx.Tx, activeKeyID string) ([]models.AssetSecret, error) {
+	SQL := `SELECT id, asset_id, field, ciphertext, wrapped_key, key_id
+			FROM asset_secrets
+			WHERE key_id<>$1
+			FOR UPDATE
+			`
+	secrets := make([]models.AssetSecret, 0)
+	err := tx.Select(&secrets, SQL, activeKeyID)
+	return secrets, err
+}
+
+func RewrapAssetSecret(tx *sqlx.Tx, secretID string, wrappedKey []byte, keyID string) error {
+	SQL := `UPDATE asset_secrets
+			SET wrapped_key=$2,
+			    key_id=$3,
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, secretID, wrappedKey, keyID)
+	return err
+}
+
+func CreateSecretAccess(tx *sqlx.Tx, assetID, field, accessedBy, sessionID, reason, ipAddress string) error {
+	SQL := `INSERT INTO secret_access_audit (asset_id, field, accessed_by, session_id, reason, ip_address)
+			VALUES ($1,$2,$3,NULLIF($4,'')::uuid,$5,NULLIF($6,''))
+			`
+	_, err := tx.Exec(SQL, assetID, field, accessedBy, sessionID, reason, ipAddress)
+	return err
+}
+
+func ListSecretAccess(assetID string) ([]models.SecretAccess, error) {
+	SQL := `SELECT a.id, a.asset_id, a.field, a.accessed_by, u.name AS accessed_by_name, a.session_id, a.reason, a.ip_address, a.accessed_at
+			FROM secret_access_audit a
+			JOIN users u ON u.id=a.accessed_by
+			WHERE a.asset_id=$1
+			ORDER BY a.accessed_at DESC
+			`
+	accesses := make([]models.SecretAccess, 0)
+	err := database.Store.Select(&accesses, SQL, assetID)
+	return accesses, err
+}
+
+// GetSpecSchema returns the spec schema of an asset type, archived or not.
+func GetSpecSchema(assetType string) (json.RawMessage, error) {
+	SQL := `SELECT spec_schema
+			FROM asset_types
+			WHERE name=$1
+			`
+	var specSchema json.RawMessage
+	err := database.Store.Get(&specSchema, SQL, assetType)
+	return specSchema, err
+}
+
+// ListAssetsWithPlaintextSecrets returns the specs of assets of a type that still carry any of the given fields.
+func ListAssetsWithPlaintextSecrets(assetType string, fields []string) ([]models.AssetSpecs, error) {
+	SQL := `SELECT id, specs
+			FROM assets
+			WHERE type=$1
+			AND specs ?| $2
+			`
+	assets := make([]models.AssetSpecs, 0)
+	err := database.Store.Select(&assets, SQL, assetType, pq.StringArray(fields))
+	return assets, err
+}
+
+func UpdateAssetSpecs(tx *sqlx.Tx, assetID string, specs json.RawMessage) error {
+	SQL := `UPDATE assets
+			SET specs=$2,
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, assetID, specs)
+	return err
+}
@@ -0,0 +1,126 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func MyAssets(userID string) ([]models.MyAsset, error) {
+	SQL := `SELECT a.id, a.brand, a.model, a.serial_number, a.type, a.status, a.warranty_end, a.specs,
+			       aa.assigned_from, aa.acknowledged_at
+			FROM asset_assignments aa
+			JOIN assets a ON a.id=aa.asset_id
+			WHERE aa.assigned_to=$1
+			AND aa.assigned_until IS NULL
+			AND a.archived_at IS NULL
+			ORDER BY aa.assigned_from DESC
+			`
+	assets := make([]models.MyAsset, 0)
+	err := database.Store.Select(&assets, SQL, userID)
+	return assets, err
+}
+
+// AcknowledgeAssignment records that the user received the asset. It returns false when the asset
+// is not assigned to the user or the receipt was already acknowledged.
+func AcknowledgeAssignment(assetID, userID string) (bool, error) {
+	SQL := `UPDATE asset_assignments
+			SET acknowledged_at=NOW()
+			WHERE asset_id=$1
+			AND assigned_to=$2
+			AND assigned_until IS NULL
+			AND acknowledged_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, assetID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+func IsAssignedTo(assetID, userID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_assignments
+			WHERE asset_id=$1
+			AND assigned_to=$2
+			AND assigned_until IS NULL
+			`
+	var assigned bool
+	err := database.Store.Get(&assigned, SQL, assetID, userID)
+	return assigned, err
+}
+
+// IsAssignedToTx is IsAssignedTo inside a transaction, for checks made under the asset lock.
+func IsAssignedToTx(tx *sqlx.Tx, assetID, userID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_assignments
+			WHERE asset_id=$1
+			AND assigned_to=$2
+			AND assigned_until IS NULL
+			`
+	var assigned bool
+	err := tx.Get(&assigned, SQL, assetID, userID)
+	return assigned, err
+}
+
+func HasPendingIssue(assetID, kind string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_issues
+			WHERE asset_id=$1
+			AND kind=$2
+			AND status='pending'
+			`
+	var exists bool
+	err := database.Store.Get(&exists, SQL, assetID, kind)
+	return exists, err
+}
+
+func CreateAssetIssue(assetID, reportedBy string, request models.ReportIssueRequest) (string, error) {
+	SQL := `INSERT INTO asset_issues (asset_id, reported_by, kind, description)
+			VALUES ($1,$2,$3,$4)
+			RETURNING id
+			`
+	var issueID string
+	err := database.Store.Get(&issueID, SQL, assetID, reportedBy, request.Kind, request.Description)
+	return issueID, err
+}
+
+const assetIssueSQL = `SELECT i.id, i.asset_id, a.serial_number, i.reported_by, u.name AS reported_by_name, i.kind,
+			       i.description, i.status, i.resolved_by, i.resolved_at, i.resolution_notes, i.created_at
+			FROM asset_issues i
+			JOIN assets a ON a.id=i.asset_id
+			JOIN users u ON u.id=i.reported_by
+			`
+
+// ListAssetIssues lists issues filtered by status and reporter, each filter is skipped when empty.
+func ListAssetIssues(status, reportedBy string) ([]models.AssetIssue, error) {
+	SQL := assetIssueSQL + `WHERE ($1 = '' OR i.status::text=$1)
+			AND ($2 = '' OR i.reported_by::text=$2)
+			ORDER BY i.created_at DESC
+			`
+	issues := make([]models.AssetIssue, 0)
+	err := database.Store.Select(&issues, SQL, status, reportedBy)
+	return issues, err
+}
+
+func GetAssetIssueForUpdate(tx *sqlx.Tx, issueID string) (models.AssetIssue, error) {
+	SQL := assetIssueSQL + `WHERE i.id=$1
+			FOR UPDATE OF i
+			`
+	var issue models.AssetIssue
+	err := tx.Get(&issue, SQL, issueID)
+	return issue, err
+}
+
+func ResolveAssetIssue(tx *sqlx.Tx, issueID, resolvedBy, status, notes string) error {
+	SQL := `UPDATE asset_issues
+			SET status=$3,
+			    resolved_by=$2,
+			    resolved_at=NOW(),
+			    resolution_notes=NULLIF($4,'')
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, issueID, resolvedBy, status, notes)
+	return err
+}
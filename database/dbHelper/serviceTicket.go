@@ -0,0 +1,111 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+const serviceTicketSQL = `SELECT st.id, st.asset_id, a.serial_number, st.fault_description, st.vendor_id, v.name AS vendor_name,
+			       st.expected_return, st.cost_estimate, st.invoice_amount, st.status, st.outcome,
+			       st.resolution_notes, st.opened_by, st.opened_at, st.closed_by, st.closed_at
+			FROM service_tickets st
+			JOIN assets a ON a.id = st.asset_id
+			LEFT JOIN vendors v ON v.id = st.vendor_id
+			`
+
+func CreateServiceTicket(tx *sqlx.Tx, assetID, openedBy string, request models.OpenServiceTicketRequest) (string, error) {
+	SQL := `INSERT INTO service_tickets (asset_id, fault_description, vendor_id, expected_return, cost_estimate, opened_by)
+			VALUES ($1,$2,NULLIF($3,'')::uuid,$4,$5,$6)
+			RETURNING id
+			`
+	var ticketID string
+	err := tx.Get(&ticketID, SQL, assetID, request.FaultDescription, request.VendorID, request.ExpectedReturn, request.CostEstimate, openedBy)
+	return ticketID, err
+}
+
+// GetServiceTicketForUpdate locks the ticket row for the rest of the transaction.
+func GetServiceTicketForUpdate(tx *sqlx.Tx, ticketID string) (models.ServiceTicket, error) {
+	SQL := serviceTicketSQL + `WHERE st.id=$1
+			FOR UPDATE OF st
+			`
+	var ticket models.ServiceTicket
+	err := tx.Get(&ticket, SQL, ticketID)
+	return ticket, err
+}
+
+func UpdateServiceTicket(tx *sqlx.Tx, ticketID string, request models.UpdateServiceTicketRequest) error {
+	SQL := `UPDATE service_tickets
+			SET status=COALESCE(NULLIF($2,'')::ticket_status, status),
+			    vendor_id=COALESCE($3, vendor_id),
+			    expected_return=COALESCE($4, expected_return),
+			    cost_estimate=COALESCE($5, cost_estimate),
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, ticketID, request.Status, request.VendorID, request.ExpectedReturn, request.CostEstimate)
+	return err
+}
+
+func CloseServiceTicket(tx *sqlx.Tx, ticketID, closedBy string, request models.CloseServiceTicketRequest) error {
+	SQL := `UPDATE service_tickets
+			SET status='closed',
+			    invoice_amount=$2,
+			    outcome=$3,
+			    resolution_notes=NULLIF($4,''),
+			    closed_by=$5,
+			    closed_at=NOW(),
+			    updated_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, ticketID, request.InvoiceAmount, request.Outcome, request.Notes, closedBy)
+	return err
+}
+
+// CloseOpenServiceTickets closes the open ticket of an asset, if any, with the status the asset
+// left the repair flow for.
+func CloseOpenServiceTickets(tx *sqlx.Tx, assetID, closedBy, outcome, notes string) error {
+	SQL := `UPDATE service_tickets
+			SET status='closed',
+			    outcome=$3,
+			    resolution_notes=NULLIF($4,''),
+			    closed_by=$2,
+			    closed_at=NOW(),
+			    updated_at=NOW()
+			WHERE asset_id=$1
+			AND status <> 'closed'
+			`
+	_, err := tx.Exec(SQL, assetID, closedBy, outcome, notes)
+	return err
+}
+
+func GetServiceTicket(ticketID string) (models.ServiceTicket, error) {
+	SQL := serviceTicketSQL + `WHERE st.id=$1`
+	var ticket models.ServiceTicket
+	err := database.Store.Get(&ticket, SQL, ticketID)
+	return ticket, err
+}
+
+// ListServiceTickets returns the tickets in the given status, or every ticket that is not
+// closed when status is empty. overdueOnly keeps the open tickets past their expected return.
+func ListServiceTickets(status, assetID string, overdueOnly bool) ([]models.ServiceTicket, error) {
+	SQL := serviceTicketSQL + `WHERE (($1 = '' AND st.status <> 'closed') OR st.status::text=$1)
+			AND ($2 = '' OR st.asset_id::text=$2)
+			AND (NOT $3 OR (st.status <> 'closed' AND st.expected_return < CURRENT_DATE))
+			ORDER BY st.expected_return NULLS LAST, st.opened_at
+			`
+	tickets := make([]models.ServiceTicket, 0)
+	err := database.Store.Select(&tickets, SQL, status, assetID, overdueOnly)
+	return tickets, err
+}
+
+func HasOpenServiceTicket(tx *sqlx.Tx, assetID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM service_tickets
+			WHERE asset_id=$1
+			AND status <> 'closed'
+			`
+	var exists bool
+	err := tx.Get(&exists, SQL, assetID)
+	return exists, err
+}
@@ -0,0 +1,128 @@
+package dbHelper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateRefreshToken(tx *sqlx.Tx, sessionID, tokenHash string, expiresAt time.Time) error {
+	SQL := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at)
+			VALUES ($1,$2,$3)
+			`
+	_, err := tx.Exec(SQL, sessionID, tokenHash, expiresAt)
+	return err
+}
+
+// GetRefreshTokenForUpdate locks a refresh token together with its session, so two concurrent
+// refreshes with the same token cannot both rotate it.
+func GetRefreshTokenForUpdate(tx *sqlx.Tx, tokenHash string) (models.RefreshToken, error) {
+	SQL := `SELECT rt.id, rt.session_id, s.user_id, u.role, rt.expires_at, rt.used_at,
+			       s.expires_at AS session_expires_at, s.archived_at AS session_archived_at
+			FROM refresh_tokens rt
+			JOIN user_session s ON s.id=rt.session_id
+			JOIN users u ON u.id=s.user_id
+			WHERE rt.token_hash=$1
+			FOR UPDATE OF rt, s
+			`
+	var token models.RefreshToken
+	err := tx.Get(&token, SQL, tokenHash)
+	return token, err
+}
+
+func MarkRefreshTokenUsed(tx *sqlx.Tx, tokenID string) error {
+	SQL := `UPDATE refresh_tokens
+			SET used_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, tokenID)
+	return err
+}
+
+func TouchSession(tx *sqlx.Tx, sessionID, userAgent, ipAddress string) error {
+	SQL := `UPDATE user_session
+			SET last_seen_at=NOW(),
+			    user_agent=COALESCE(NULLIF($2,''), user_agent),
+			    ip_address=COALESCE(NULLIF($3,''), ip_address)
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, sessionID, userAgent, ipAddress)
+	return err
+}
+
+func RevokeSession(tx *sqlx.Tx, sessionID, reason string) error {
+	SQL := `UPDATE user_session
+			SET archived_at=NOW(),
+			    revoked_reason=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, sessionID, reason)
+	return err
+}
+
+func ListUserSessions(userID string) ([]models.Session, error) {
+	SQL := `SELECT id, user_agent, ip_address, created_at, last_seen_at, expires_at
+			FROM user_session
+			WHERE user_id=$1
+			AND archived_at IS NULL
+			AND expires_at > NOW()
+			ORDER BY COALESCE(last_seen_at, created_at) DESC
+			`
+	sessions := make([]models.Session, 0)
+	err := database.Store.Select(&sessions, SQL, userID)
+	return sessions, err
+}
+
+func RevokeUserSession(userID, sessionID string) error {
+	SQL := `UPDATE user_session
+			SET archived_at=NOW(),
+			    revoked_reason='revoked'
+			WHERE id=$1
+			AND user_id=$2
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except the current one.
+func RevokeOtherSessions(userID, currentSessionID string) (int64, error) {
+	SQL := `UPDATE user_session
+			SET archived_at=NOW(),
+			    revoked_reason='revoked'
+			WHERE user_id=$1
+			AND id<>$2
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, userID, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RevokeUserSessions revokes every active session of a user.
+func RevokeUserSessions(tx *sqlx.Tx, userID, reason string) error {
+	SQL := `UPDATE user_session
+			SET archived_at=NOW(),
+			    revoked_reason=$2
+			WHERE user_id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, userID, reason)
+	return err
+}
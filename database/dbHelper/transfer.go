@@ -0,0 +1,134 @@
+package dbHelper
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateTransfer(tx *sqlx.Tx, assetID, fromUser string, request models.TransferRequest) (string, error) {
+	SQL := `INSERT INTO asset_transfers (asset_id, from_user, to_user, message)
+			VALUES ($1,$2,$3,NULLIF($4,''))
+			RETURNING id
+			`
+	var transferID string
+	err := tx.Get(&transferID, SQL, assetID, fromUser, request.ToUserID, request.Message)
+	return transferID, err
+}
+
+func HasOpenTransfer(tx *sqlx.Tx, assetID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM asset_transfers
+			WHERE asset_id=$1
+			AND status IN ('pending_recipient', 'pending_approval')
+			`
+	var open bool
+	err := tx.Get(&open, SQL, assetID)
+	return open, err
+}
+
+const transferSQL = `SELECT t.id, t.asset_id, a.serial_number, a.type, t.from_user, f.name AS from_user_name,
+			       t.to_user, u.name AS to_user_name, t.message, t.status, t.created_at, t.accepted_at,
+			       t.decided_by, t.decided_at, t.decision_notes, t.closed_at
+			FROM asset_transfers t
+			JOIN assets a ON a.id=t.asset_id
+			JOIN users f ON f.id=t.from_user
+			JOIN users u ON u.id=t.to_user
+			`
+
+// ListUserTransfers lists the transfers a user sent or received.
+func ListUserTransfers(userID string) ([]models.AssetTransfer, error) {
+	SQL := transferSQL + `WHERE t.from_user=$1
+			OR t.to_user=$1
+			ORDER BY t.created_at DESC
+			`
+	transfers := make([]models.AssetTransfer, 0)
+	err := database.Store.Select(&transfers, SQL, userID)
+	return transfers, err
+}
+
+// ListTransfers lists transfers filtered by status, skipped when empty.
+func ListTransfers(status string) ([]models.AssetTransfer, error) {
+	SQL := transferSQL + `WHERE ($1 = '' OR t.status::text=$1)
+			ORDER BY t.created_at DESC
+			`
+	transfers := make([]models.AssetTransfer, 0)
+	err := database.Store.Select(&transfers, SQL, status)
+	return transfers, err
+}
+
+func GetTransferForUpdate(tx *sqlx.Tx, transferID string) (models.AssetTransfer, error) {
+	SQL := transferSQL + `WHERE t.id=$1
+			FOR UPDATE OF t
+			`
+	var transfer models.AssetTransfer
+	err := tx.Get(&transfer, SQL, transferID)
+	return transfer, err
+}
+
+func AcceptTransfer(tx *sqlx.Tx, transferID string) error {
+	SQL := `UPDATE asset_transfers
+			SET accepted_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, transferID)
+	return err
+}
+
+// AcknowledgeTransferredAssignment marks the open assignment of a transferred asset as received
+// when the recipient accepted the transfer.
+func AcknowledgeTransferredAssignment(tx *sqlx.Tx, assetID, transferID string) error {
+	SQL := `UPDATE asset_assignments aa
+			SET acknowledged_at=t.accepted_at
+			FROM asset_transfers t
+			WHERE t.id=$2
+			AND aa.asset_id=$1
+			AND aa.assigned_until IS NULL
+			`
+	_, err := tx.Exec(SQL, assetID, transferID)
+	return err
+}
+
+func UpdateTransferStatus(tx *sqlx.Tx, transferID, status string) error {
+	SQL := `UPDATE asset_transfers
+			SET status=$2
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, transferID, status)
+	return err
+}
+
+// DecideTransfer records the asset manager deciding on a transfer waiting for approval.
+func DecideTransfer(tx *sqlx.Tx, transferID, decidedBy, notes string) error {
+	SQL := `UPDATE asset_transfers
+			SET decided_by=$2,
+			    decided_at=NOW(),
+			    decision_notes=NULLIF($3,'')
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, transferID, decidedBy, notes)
+	return err
+}
+
+// CloseTransfer moves a transfer to a final status.
+func CloseTransfer(tx *sqlx.Tx, transferID, status string) error {
+	SQL := `UPDATE asset_transfers
+			SET status=$2,
+			    closed_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, transferID, status)
+	return err
+}
+
+// CancelOpenTransfers cancels the transfers of an asset that are still waiting.
+func CancelOpenTransfers(tx *sqlx.Tx, assetID string) error {
+	SQL := `UPDATE asset_transfers
+			SET status='cancelled',
+			    closed_at=NOW()
+			WHERE asset_id=$1
+			AND status IN ('pending_recipient', 'pending_approval')
+			`
+	_, err := tx.Exec(SQL, assetID)
+	return err
+}
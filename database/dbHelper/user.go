@@ -1,6 +1,7 @@
 package dbHelper
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -20,17 +21,17 @@ func IsUserExist(email string) (bool, error) {
 	return exists, err
 }
 
-func CreateUser(name, email, userRole, userType, phoneNumber, password string) (string, error) {
-	SQL := `INSERT INTO users (name,email,role,type,phone_no,password)
-			VALUES ($1,LOWER(TRIM($2)),$3,$4,$5,$6)
+func CreateUser(name, email, userRole, userType, phoneNumber, password string, customFields json.RawMessage) (string, error) {
+	SQL := `INSERT INTO users (name,email,role,type,phone_no,password,custom_fields)
+			VALUES ($1,LOWER(TRIM($2)),$3,$4,$5,$6,$7)
 			RETURNING id`
 	var userID string
-	err := database.Store.Get(&userID, SQL, name, email, userRole, userType, phoneNumber, password)
+	err := database.Store.Get(&userID, SQL, name, email, userRole, userType, phoneNumber, password, customFields)
 	return userID, err
 }
 
 func GetUserByEmail(email string) (models.User, error) {
-	SQL := `SELECT id, password,email
+	SQL := `SELECT id, name, password, email, role, email_verified_at
 			FROM USERS 
 			WHERE email=TRIM(LOWER($1)) 
 			AND 
@@ -44,36 +45,53 @@ func GetUserByEmail(email string) (models.User, error) {
 
 	return User, nil
 }
-func CreateUserSession(id string) (string, error) {
-	SQL := `INSERT INTO user_session (user_id)
-			VALUES ($1) RETURNING id
+func GetUserByID(userID string) (models.User, error) {
+	SQL := `SELECT id, name, email, role, type AS employment, email_verified_at
+			FROM users
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	var user models.User
+	err := database.Store.Get(&user, SQL, userID)
+	return user, err
+}
+func CreateUserSession(tx *sqlx.Tx, id, userAgent, ipAddress string, expiresAt time.Time, mfaVerified bool) (string, error) {
+	SQL := `INSERT INTO user_session (user_id, user_agent, ip_address, last_seen_at, expires_at, mfa_verified)
+			VALUES ($1, NULLIF($2,''), NULLIF($3,''), NOW(), $4, $5) RETURNING id
 			`
 	var sessionID string
-	err := database.Store.Get(&sessionID, SQL, id)
+	err := tx.Get(&sessionID, SQL, id, userAgent, ipAddress, expiresAt, mfaVerified)
 	if err != nil {
 		return "", err
 	}
 	return sessionID, nil
 }
-func VaidateSession(sessionID string) (string, error) {
-	SQL := `SELECT user_id 
+
+// VaidateSession returns the user of an active session and whether the session passed MFA.
+func VaidateSession(sessionID string) (string, bool, error) {
+	SQL := `SELECT user_id, mfa_verified
 			FROM user_session
 			WHERE id =$1 AND
 			archived_at IS NULL
+			AND expires_at > NOW()
 			`
-	var userID string
-	err := database.Store.Get(&userID, SQL, sessionID)
+	var session struct {
+		UserID      string `db:"user_id"`
+		MFAVerified bool   `db:"mfa_verified"`
+	}
+	err := database.Store.Get(&session, SQL, sessionID)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
-	return userID, nil
+	return session.UserID, session.MFAVerified, nil
 }
 
 func DeleteSessionByToken(sessionID string) error {
 	SQL := `UPDATE user_session
-			SET archived_at= NOW()
+			SET archived_at= NOW(),
+			    revoked_reason='logout'
 			WHERE id=$1
-			AND archiced_at IS NULL 
+			AND archived_at IS NULL 
 			`
 	result, err := database.Store.Exec(SQL, sessionID)
 	if err != nil {
@@ -103,8 +121,9 @@ func DeleteSessionByToken(sessionID string) error {
 //}
 
 func CreateAsset(tx *sqlx.Tx, assetRequest models.Asset) (string, error) {
-	SQL := `INSERT INTO assets (brand, model, serial_number ,type ,status ,owner ,warranty_start ,warranty_end)
-			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	SQL := `INSERT INTO assets (brand, model, serial_number ,type ,status ,owner ,warranty_start ,warranty_end ,purchase_vendor_id,
+			                    purchase_date ,purchase_price ,currency ,invoice_number ,specs ,custom_fields)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NULLIF(UPPER($12),''),NULLIF($13,''),$14,$15)
 			RETURNING id
 			`
 	var assetID string
@@ -117,6 +136,13 @@ func CreateAsset(tx *sqlx.Tx, assetRequest models.Asset) (string, error) {
 		assetRequest.Owner,
 		assetRequest.WarrantyStart,
 		assetRequest.WarrantyEnd,
+		assetRequest.PurchaseVendorID,
+		assetRequest.PurchaseDate,
+		assetRequest.PurchasePrice,
+		assetRequest.Currency,
+		assetRequest.InvoiceNumber,
+		assetRequest.Specs,
+		assetRequest.CustomFields,
 	}
 	err := tx.Get(&assetID, SQL, args...)
 	if err != nil {
@@ -127,8 +153,8 @@ func CreateAsset(tx *sqlx.Tx, assetRequest models.Asset) (string, error) {
 }
 
 // FETCH ASSETS
-func ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberStr string, limit, offset int) ([]models.AssetInfo, error) {
-	SQL := `SELECT brand ,model ,type ,serial_number ,status ,owner ,created_at
+func ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberStr, locationID string, customFieldFilter json.RawMessage, limit, offset int) ([]models.AssetInfo, error) {
+	SQL := `SELECT brand ,model ,type ,serial_number ,asset_tag ,status ,owner ,location_id ,custom_fields ,created_at
 			FROM assets
 			WHERE archived_at IS NULL 
 			AND (
@@ -146,11 +172,18 @@ func ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberSt
 			AND(
 			    $5='' or status::text LIKE '%'||$5||'%'
 			)
+			AND(
+			    $5<>'' or status<>'disposed'
+			)
 			AND(
 			    $6=''or owner::text LIKE '%'||$6||'%'
 			)
+			AND(
+			    $10='' or location_id IN (SELECT location_subtree($10))
+			)
+			AND custom_fields @> $7
 			ORDER BY created_at
-			LIMIT $7 OFFSET $8
+			LIMIT $8 OFFSET $9
 			`
 
 	assets := make([]models.AssetInfo, 0)
@@ -173,13 +206,16 @@ func ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberSt
 	//	return res, DashboardErr
 	//}
 
-	err := database.Store.Select(&assets, SQL, brandStr, modelStr, serialNumberStr, typeStr, statusStr, ownerStr, limit, offset)
+	err := database.Store.Select(&assets, SQL, brandStr, modelStr, serialNumberStr, typeStr, statusStr, ownerStr, customFieldFilter, limit, offset, locationID)
 	if err != nil {
 		return assets, err
 	}
 	return assets, nil
 }
-func DashboardData() (models.DashboardSummary, error) {
+
+// DashboardData counts the assets per status, only those kept at a location or below it when
+// one is given.
+func DashboardData(locationID string) (models.DashboardSummary, error) {
 	var summary models.DashboardSummary
 
 	Sql := `SELECT
@@ -188,83 +224,22 @@ func DashboardData() (models.DashboardSummary, error) {
 			COUNT(*) FILTER (WHERE status = 'assigned') AS assigned,
 			COUNT(*) FILTER (WHERE status = 'for_repair') AS waiting_for_repair,
 			COUNT(*) FILTER (WHERE status = 'in_service') AS in_service,
-			COUNT(*) FILTER (WHERE status = 'damaged') AS damaged
+			COUNT(*) FILTER (WHERE status = 'damaged') AS damaged,
+			COUNT(*) FILTER (WHERE status = 'reserved') AS reserved
 		FROM assets
-		WHERE archived_at IS NULL`
+		WHERE archived_at IS NULL
+		AND status <> 'disposed'
+		AND ($1 = '' OR location_id IN (SELECT location_subtree($1)))`
 
 	//var res models.DashboardData
-	DashboardErr := database.Store.Get(&summary, Sql)
+	DashboardErr := database.Store.Get(&summary, Sql, locationID)
 	if DashboardErr != nil {
 		return summary, DashboardErr
 	}
 	return summary, nil
 
 }
-func CreateLaptop(tx *sqlx.Tx, assetID string, assetRequest models.LaptopSpecs) error {
-	SQL := `INSERT INTO laptop (asset_id,processor,ram,storage,operating_system,charger,device_password)
-			VALUES ($1,$2,$3,$4,$5,$6,$7)`
-	args := []interface{}{
-		assetID,
-		assetRequest.Processor,
-		assetRequest.Ram,
-		assetRequest.Storage,
-		assetRequest.OperatingSystem,
-		assetRequest.Charger,
-		assetRequest.DevicePassword,
-	}
-	_, err := tx.Exec(SQL, args...)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-func CreateKeyboard(tx *sqlx.Tx, assetID string, assetRequest models.KeyboardSpecs) error {
-	SQL := `INSERT INTO keyboard(asset_id,layout,connectivity)
-			VALUES($1,$2,$3)`
-	args := []interface{}{
-		assetID,
-		assetRequest.Layout,
-		assetRequest.Connectivity,
-	}
-	_, err := tx.Exec(SQL, args...)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-func CreateMouse(tx *sqlx.Tx, assetID string, assetRequest models.MouseSpecs) error {
-	SQL := `INSERT INTO mouse (asset_id,dpi,connectivity)
-			VALUES($1,$2,$3)`
-	args := []interface{}{
-		assetID,
-		assetRequest.Connectivity,
-		assetRequest.Dpi,
-	}
-	_, err := tx.Exec(SQL, args...)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-func CreateMobile(tx *sqlx.Tx, assetID string, assetRequest models.MobileSpecs) error {
-	SQL := `INSERT INTO mobile (asset_id,operating_system,ram,storage,charger,device_password)
-			VALUES ($1,$2,$3,$4,$5,$6)`
-
-	args := []interface{}{
-		assetID,
-		assetRequest.OperatingSystem,
-		assetRequest.Ram,
-		assetRequest.Storage,
-		assetRequest.Charger,
-		assetRequest.DevicePassword,
-	}
-	_, err := tx.Exec(SQL, args...)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-func AssignedAssets(id, assignedById, assignedTo string) error {
+func AssignedAssets(tx *sqlx.Tx, id, assignedById, assignedTo string) error {
 	SQL := `UPDATE assets
 			SET assigned_by_id =$1,
 			    assigned_to=$2,
@@ -274,26 +249,44 @@ func AssignedAssets(id, assignedById, assignedTo string) error {
 			WHERE id=$3
 			AND archived_at IS NULL 
 			    `
-	_, err := database.Store.Exec(SQL, assignedById, assignedTo, id)
+	result, err := tx.Exec(SQL, assignedById, assignedTo, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return errors.New("asset not found")
+	}
 	return nil
 }
-func ServiceAssets(assetID string, serviceStart, serviceEnd, returnedOn time.Time) error {
+func ReturnAsset(tx *sqlx.Tx, assetID, receivedBy, condition, notes, status string) error {
 	SQL := `UPDATE assets
-			SET service_start=$1
-			service_end=$2
-			returned_on=$3
-			WHERE id=$4
-			    AND status !='assigned'
-			    AND status != 'available'
-			AND archived_at IS NULL 
+			SET assigned_to=NULL,
+			    assigned_on=NULL,
+			    received_by_id=$1,
+			    received_on=NOW(),
+			    return_condition=$2,
+			    return_notes=NULLIF($3,''),
+			    status=$4,
+			    updated_at=NOW()
+			WHERE id=$5
+			AND status='assigned'
+			AND archived_at IS NULL
 			`
-	_, err := database.Store.Exec(SQL, serviceStart, serviceEnd, returnedOn, assetID)
+	result, err := tx.Exec(SQL, receivedBy, condition, notes, status, assetID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return errors.New("asset is not assigned")
+	}
 	return nil
 }
 func DeleteAsset(archivedBy, assetID string) error {
@@ -321,16 +314,17 @@ func GetAssetInfo(userID, assetStatus string) ([]models.AssetInfoRequest, error)
 	err := database.Store.Select(&assetDetails, SQL, userID, assetStatus)
 	return assetDetails, err
 }
-func GetUserInfo(name, role, userType, assetStatus string) ([]models.UserInfoRequest, error) {
+func GetUserInfo(name, role, userType, assetStatus string, customFieldFilter json.RawMessage) ([]models.UserInfoRequest, error) {
 	SQL := `
-		SELECT id, name, email, phone_number, role, type, created_at
+		SELECT id, name, email, phone_no, role, type, created_at, custom_fields
 		FROM users
 		WHERE ($1 = '' OR name LIKE '%' || $1 || '%')
 		AND ($2 = '' OR role::TEXT=$2)
 		AND ($3 = '' OR type::TEXT=$3)
+		AND custom_fields @> $4
 	`
 	users := make([]models.UserInfoRequest, 0)
-	err := database.Store.Select(&users, SQL, name, role, userType)
+	err := database.Store.Select(&users, SQL, name, role, userType, customFieldFilter)
 	if err != nil {
 		return users, err
 	}
@@ -352,7 +346,7 @@ func GetUserInfo(name, role, userType, assetStatus string) ([]models.UserInfoReq
 }
 func FetchUser(userID string) (models.UserInfoRequest, error) {
 	SQL := `
-		SELECT id, name, email, phone_no, role, type, created_at
+		SELECT id, name, email, phone_no, role, type, created_at, custom_fields
 		FROM users
 		WHERE archived_at IS NULL 
 		AND id=$1
@@ -389,87 +383,60 @@ func GetAsset(userID string) ([]models.AssetInfoRequest, error) {
 	return assetDetails, err
 }
 
-func UpdateAsset(tx *sqlx.Tx, assetID, brand, model, serialNo, assetType, owner string, warrantyStart, warrantyEnd time.Time) error {
+func UpdateAsset(tx *sqlx.Tx, assetID string, request models.UpdateAssetRequest) error {
 	query := `UPDATE assets
-            set brand = $2, model = $3, serial_no = $4, type=$5,owner=$6,warranty_start = $7,warranty_end=$8, updated_at =now()
+            set brand = $2, model = $3, serial_number = $4, type=$5,owner=$6,warranty_start = $7,warranty_end=$8, purchase_vendor_id=$9,
+                purchase_date=$10, purchase_price=$11, currency=NULLIF(UPPER($12),''), invoice_number=NULLIF($13,''), specs=$14, custom_fields=$15, updated_at =now()
             where id= $1 and archived_at is null `
-	_, err := tx.Exec(query, assetID, brand, model, serialNo, assetType, owner, warrantyStart, warrantyEnd)
+	result, err := tx.Exec(query,
+		assetID,
+		request.Brand,
+		request.Model,
+		request.SerialNo,
+		request.Type,
+		request.Owner,
+		request.WarrantyStart,
+		request.WarrantyEnd,
+		request.PurchaseVendorID,
+		request.PurchaseDate,
+		request.PurchasePrice,
+		request.Currency,
+		request.InvoiceNumber,
+		request.Specs,
+		request.CustomFields,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return errors.New("asset not found")
+	}
 	return nil
-
-}
-func UpdateLaptop(tx *sqlx.Tx, assetID string, laptop *models.LaptopSpecs) error {
-	query := `
-    UPDATE laptop
-    SET
-        processor = $2,
-        ram = $3,
-        storage = $4,
-        os = $5,
-        charger = $6,
-        password = $7
-    WHERE asset_id = $1
-    `
-
-	_, err := tx.Exec(query,
-		assetID,
-		laptop.Processor,
-		laptop.Ram,
-		laptop.Storage,
-		laptop.OperatingSystem,
-		laptop.Charger,
-		laptop.DevicePassword,
-	)
-
-	return err
-}
-func UpdateMouse(tx *sqlx.Tx, assetID string, mouse *models.MouseSpecs) error {
-	query := `
-    UPDATE mouse
-    SET
-        dpi = $2,
-        connectivity = $3
-    WHERE asset_id = $1
-    `
-
-	_, err := tx.Exec(query, assetID, mouse.Dpi, mouse.Connectivity)
-	return err
 }
-func UpdateKeyboard(tx *sqlx.Tx, assetID string, keyboard *models.KeyboardSpecs) error {
-	query := `
-    UPDATE keyboard
-    SET
-        layout = $2,
-        connectivity = $3
-    WHERE asset_id = $1
-    `
 
-	_, err := tx.Exec(query, assetID, keyboard.Layout, keyboard.Connectivity)
-	return err
+// GetAssetStatusForUpdate locks the asset row for the rest of the transaction and returns its status.
+func GetAssetStatusForUpdate(tx *sqlx.Tx, assetID string) (string, error) {
+	SQL := `SELECT status
+			FROM assets
+			WHERE id=$1
+			AND archived_at IS NULL
+			FOR UPDATE
+			`
+	var status string
+	err := tx.Get(&status, SQL, assetID)
+	return status, err
 }
-func UpdateMobile(tx *sqlx.Tx, assetID string, mobile *models.MobileSpecs) error {
-	query := `
-    UPDATE mobile
-    SET
-        os = $2,
-        ram = $3,
-        storage = $4,
-        charger = $5,
-        password = $6
-    WHERE asset_id = $1
-    `
-
-	_, err := tx.Exec(
-		query,
-		assetID,
-		mobile.OperatingSystem,
-		mobile.Ram,
-		mobile.Storage,
-		mobile.Charger,
-		mobile.DevicePassword,
-	)
-
+func UpdateAssetStatus(tx *sqlx.Tx, assetID, status string) error {
+	SQL := `UPDATE assets
+			SET status=$1,
+			    updated_at=NOW()
+			WHERE id=$2
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, status, assetID)
 	return err
 }
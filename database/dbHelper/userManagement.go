@@ -0,0 +1,104 @@
+package dbHelper
+
+import (
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+// CreateInvitedUser creates an account without a usable password, it is set when the invitation is accepted.
+func CreateInvitedUser(tx *sqlx.Tx, request models.InviteUserRequest, customFields json.RawMessage, invitedBy string) (string, error) {
+	SQL := `INSERT INTO users (name, email, role, type, phone_no, password, custom_fields, invited_by_id)
+			VALUES ($1,LOWER(TRIM($2)),$3,$4,$5,'',$6,$7)
+			RETURNING id`
+	var userID string
+	err := tx.Get(&userID, SQL, request.Name, request.Email, request.Role, request.Type, request.PhoneNumber, customFields, invitedBy)
+	return userID, err
+}
+
+// GetPendingInvite returns an invited user who has not accepted the invitation yet.
+func GetPendingInvite(userID string) (models.User, error) {
+	SQL := `SELECT id, name, email, role
+			FROM users
+			WHERE id=$1
+			AND invited_by_id IS NOT NULL
+			AND invite_accepted_at IS NULL
+			AND archived_at IS NULL
+			`
+	var user models.User
+	err := database.Store.Get(&user, SQL, userID)
+	return user, err
+}
+
+// AcceptInvite sets the password of an invited user. Following the emailed link also proves the email.
+func AcceptInvite(tx *sqlx.Tx, userID, password string) error {
+	SQL := `UPDATE users
+			SET password=$2,
+			    invite_accepted_at=NOW(),
+			    email_verified_at=COALESCE(email_verified_at, NOW())
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, userID, password)
+	return err
+}
+
+func UpdateUserRole(tx *sqlx.Tx, userID, role string) error {
+	SQL := `UPDATE users
+			SET role=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, userID, role)
+	return err
+}
+
+// LockActiveUser locks an active user row, so the user cannot be archived before the transaction
+// ends. exclusive takes the lock archiving itself needs; other callers share it.
+func LockActiveUser(tx *sqlx.Tx, userID string, exclusive bool) error {
+	lock := "FOR SHARE"
+	if exclusive {
+		lock = "FOR UPDATE"
+	}
+	SQL := `SELECT id
+			FROM users
+			WHERE id=$1
+			AND archived_at IS NULL
+			` + lock
+	var lockedID string
+	return tx.Get(&lockedID, SQL, userID)
+}
+
+func ArchiveUser(tx *sqlx.Tx, userID, archivedBy string) error {
+	SQL := `UPDATE users
+			SET archived_at=NOW(),
+			    archived_by_id=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, userID, archivedBy)
+	return err
+}
+
+func GetArchivedUser(userID string) (models.User, error) {
+	SQL := `SELECT id, name, email, role
+			FROM users
+			WHERE id=$1
+			AND archived_at IS NOT NULL
+			`
+	var user models.User
+	err := database.Store.Get(&user, SQL, userID)
+	return user, err
+}
+
+func UnarchiveUser(userID string) error {
+	SQL := `UPDATE users
+			SET archived_at=NULL,
+			    archived_by_id=NULL
+			WHERE id=$1
+			`
+	_, err := database.Store.Exec(SQL, userID)
+	return err
+}
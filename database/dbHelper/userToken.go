@@ -0,0 +1,68 @@
+package dbHelper
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+// CreateUserToken stores a new token for the purpose and retires the unused ones issued before it.
+func CreateUserToken(tx *sqlx.Tx, userID, purpose, tokenHash string, expiresAt time.Time) error {
+	SQL := `UPDATE user_tokens
+			SET used_at=NOW()
+			WHERE user_id=$1
+			AND purpose=$2
+			AND used_at IS NULL
+			`
+	if _, err := tx.Exec(SQL, userID, purpose); err != nil {
+		return err
+	}
+	SQL = `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+			VALUES ($1,$2,$3,$4)
+			`
+	_, err := tx.Exec(SQL, userID, purpose, tokenHash, expiresAt)
+	return err
+}
+
+func GetUserTokenForUpdate(tx *sqlx.Tx, purpose, tokenHash string) (models.UserToken, error) {
+	SQL := `SELECT t.id, t.user_id, t.purpose, t.expires_at, t.used_at
+			FROM user_tokens t
+			JOIN users u ON u.id=t.user_id
+			WHERE t.token_hash=$1
+			AND t.purpose=$2
+			AND u.archived_at IS NULL
+			FOR UPDATE OF t
+			`
+	var token models.UserToken
+	err := tx.Get(&token, SQL, tokenHash, purpose)
+	return token, err
+}
+
+func MarkUserTokenUsed(tx *sqlx.Tx, tokenID string) error {
+	SQL := `UPDATE user_tokens
+			SET used_at=NOW()
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, tokenID)
+	return err
+}
+
+func UpdateUserPassword(tx *sqlx.Tx, userID, password string) error {
+	SQL := `UPDATE users
+			SET password=$2
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	_, err := tx.Exec(SQL, userID, password)
+	return err
+}
+
+func MarkEmailVerified(tx *sqlx.Tx, userID string) error {
+	SQL := `UPDATE users
+			SET email_verified_at=COALESCE(email_verified_at, NOW())
+			WHERE id=$1
+			`
+	_, err := tx.Exec(SQL, userID)
+	return err
+}
@@ -0,0 +1,151 @@
+package dbHelper
+
+import (
+	"errors"
+
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func CreateVendor(request models.VendorRequest) (string, error) {
+	SQL := `INSERT INTO vendors (name, contact_name, email, phone_no, sla_days, notes)
+			VALUES (TRIM($1),NULLIF($2,''),NULLIF(LOWER(TRIM($3)),''),NULLIF($4,''),$5,NULLIF($6,''))
+			RETURNING id
+			`
+	var vendorID string
+	err := database.Store.Get(&vendorID, SQL, request.Name, request.ContactName, request.Email, request.PhoneNumber, request.SLADays, request.Notes)
+	return vendorID, err
+}
+
+func IsVendorExist(name, excludeID string) (bool, error) {
+	SQL := `SELECT count(*)>0
+			FROM vendors
+			WHERE LOWER(name)=LOWER(TRIM($1))
+			AND ($2 = '' OR id::text <> $2)
+			AND archived_at IS NULL
+			`
+	var exists bool
+	err := database.Store.Get(&exists, SQL, name, excludeID)
+	return exists, err
+}
+
+func ListVendors(name string) ([]models.Vendor, error) {
+	SQL := `SELECT id, name, contact_name, email, phone_no, sla_days, notes, created_at, updated_at
+			FROM vendors
+			WHERE archived_at IS NULL
+			AND ($1 = '' OR name ILIKE '%' || $1 || '%')
+			ORDER BY name
+			`
+	vendors := make([]models.Vendor, 0)
+	err := database.Store.Select(&vendors, SQL, name)
+	return vendors, err
+}
+
+func GetVendor(vendorID string) (models.Vendor, error) {
+	SQL := `SELECT id, name, contact_name, email, phone_no, sla_days, notes, created_at, updated_at
+			FROM vendors
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	var vendor models.Vendor
+	err := database.Store.Get(&vendor, SQL, vendorID)
+	return vendor, err
+}
+
+func UpdateVendor(vendorID string, request models.VendorRequest) error {
+	SQL := `UPDATE vendors
+			SET name=TRIM($2),
+			    contact_name=NULLIF($3,''),
+			    email=NULLIF(LOWER(TRIM($4)),''),
+			    phone_no=NULLIF($5,''),
+			    sla_days=$6,
+			    notes=NULLIF($7,''),
+			    updated_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, vendorID, request.Name, request.ContactName, request.Email, request.PhoneNumber, request.SLADays, request.Notes)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("vendor not found")
+	}
+	return nil
+}
+
+func DeleteVendor(vendorID string) error {
+	SQL := `UPDATE vendors
+			SET archived_at=NOW()
+			WHERE id=$1
+			AND archived_at IS NULL
+			`
+	result, err := database.Store.Exec(SQL, vendorID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("vendor not found")
+	}
+	return nil
+}
+
+// VendorReport sums up, per vendor, the assets bought from it (spend per currency) and the
+// repairs it handled. Turnaround is measured from a ticket being opened to it being closed.
+func VendorReport() ([]models.VendorReport, error) {
+	SQL := `WITH tickets AS (
+				SELECT st.vendor_id,
+				       COUNT(*) AS tickets_opened,
+				       COUNT(*) FILTER (WHERE st.status = 'closed') AS tickets_closed,
+				       COALESCE(SUM(st.invoice_amount) FILTER (WHERE st.status = 'closed'), 0) AS repair_spend,
+				       AVG(EXTRACT(EPOCH FROM st.closed_at - st.opened_at) / 86400) FILTER (WHERE st.status = 'closed') AS avg_turnaround_days,
+				       MAX(EXTRACT(EPOCH FROM st.closed_at - st.opened_at) / 86400) FILTER (WHERE st.status = 'closed') AS max_turnaround_days,
+				       COUNT(*) FILTER (
+				           WHERE v.sla_days IS NOT NULL
+				           AND COALESCE(st.closed_at, NOW()) - st.opened_at > make_interval(days => v.sla_days)
+				       ) AS tickets_breaching_sla
+				FROM service_tickets st
+				JOIN vendors v ON v.id = st.vendor_id
+				GROUP BY st.vendor_id
+			),
+			purchases AS (
+				SELECT vendor_id,
+				       SUM(assets_purchased)::int AS assets_purchased,
+				       jsonb_object_agg(currency, spend) FILTER (WHERE currency IS NOT NULL) AS purchase_spend
+				FROM (
+					SELECT purchase_vendor_id AS vendor_id, currency,
+					       COUNT(*) AS assets_purchased,
+					       SUM(purchase_price) AS spend
+					FROM assets
+					WHERE purchase_vendor_id IS NOT NULL
+					GROUP BY purchase_vendor_id, currency
+				) p
+				GROUP BY vendor_id
+			)
+			SELECT v.id, v.name, v.sla_days,
+			       COALESCE(p.assets_purchased, 0) AS assets_purchased,
+			       COALESCE(p.purchase_spend, '{}'::jsonb) AS purchase_spend,
+			       COALESCE(t.tickets_opened, 0) AS tickets_opened,
+			       COALESCE(t.tickets_closed, 0) AS tickets_closed,
+			       COALESCE(t.repair_spend, 0) AS repair_spend,
+			       t.avg_turnaround_days,
+			       t.max_turnaround_days,
+			       COALESCE(t.tickets_breaching_sla, 0) AS tickets_breaching_sla
+			FROM vendors v
+			LEFT JOIN tickets t ON t.vendor_id = v.id
+			LEFT JOIN purchases p ON p.vendor_id = v.id
+			WHERE v.archived_at IS NULL
+			ORDER BY v.name
+			`
+	report := make([]models.VendorReport, 0)
+	err := database.Store.Select(&report, SQL)
+	return report, err
+}
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TYPE asset_condition AS ENUM (
+    'good',
+    'fair',
+    'needs_repair',
+    'damaged'
+);
+
+ALTER TABLE assets
+    ADD COLUMN received_by_id   UUID REFERENCES users(id),
+    ADD COLUMN received_on      TIMESTAMPTZ,
+    ADD COLUMN return_condition asset_condition,
+    ADD COLUMN return_notes     TEXT;
+
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE asset_assignments (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id         UUID            NOT NULL REFERENCES assets(id),
+    assigned_to      UUID            NOT NULL REFERENCES users(id),
+    assigned_by_id   UUID REFERENCES users(id),
+    assigned_from    TIMESTAMPTZ     NOT NULL DEFAULT now(),
+    assigned_until   TIMESTAMPTZ,
+    received_by_id   UUID REFERENCES users(id),
+    return_condition asset_condition,
+    return_notes     TEXT,
+    created_at       TIMESTAMPTZ     DEFAULT now()
+);
+
+CREATE INDEX idx_asset_assignments_asset
+    ON asset_assignments(asset_id, assigned_from);
+
+CREATE INDEX idx_asset_assignments_user
+    ON asset_assignments(assigned_to, assigned_from);
+
+CREATE UNIQUE INDEX idx_open_asset_assignment
+    ON asset_assignments(asset_id)
+    WHERE assigned_until IS NULL;
+
+INSERT INTO asset_assignments (asset_id, assigned_to, assigned_by_id, assigned_from)
+SELECT id, assigned_to, assigned_by_id, COALESCE(assigned_on, now())
+FROM assets
+WHERE assigned_to IS NOT NULL
+AND archived_at IS NULL;
+
+COMMIT;
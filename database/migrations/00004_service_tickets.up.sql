@@ -0,0 +1,65 @@
+BEGIN;
+
+CREATE TYPE ticket_status AS ENUM (
+    'open',
+    'in_progress',
+    'awaiting_parts',
+    'closed'
+);
+
+CREATE TABLE service_tickets (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id          UUID          NOT NULL REFERENCES assets(id),
+    fault_description TEXT          NOT NULL,
+    vendor            TEXT,
+    expected_return   DATE,
+    cost_estimate     NUMERIC(12,2),
+    invoice_amount    NUMERIC(12,2),
+    status            ticket_status NOT NULL DEFAULT 'open',
+    outcome           asset_status,
+    resolution_notes  TEXT,
+    opened_by         UUID REFERENCES users(id),
+    opened_at         TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    closed_by         UUID REFERENCES users(id),
+    closed_at         TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_open_service_ticket
+    ON service_tickets(asset_id)
+    WHERE status <> 'closed';
+
+CREATE INDEX idx_service_tickets_status
+    ON service_tickets(status, expected_return);
+
+-- keep the repairs that were recorded on the asset row before tickets existed
+INSERT INTO service_tickets (asset_id, fault_description, expected_return, status, outcome, opened_at, closed_at)
+SELECT id,
+       'recorded before service tickets',
+       service_end::date,
+       CASE WHEN status IN ('for_repair', 'in_service') AND returned_on IS NULL
+            THEN 'in_progress'::ticket_status
+            ELSE 'closed'::ticket_status END,
+       CASE WHEN status IN ('for_repair', 'in_service') AND returned_on IS NULL
+            THEN NULL
+            ELSE status END,
+       service_start,
+       CASE WHEN status IN ('for_repair', 'in_service') AND returned_on IS NULL
+            THEN NULL
+            ELSE COALESCE(returned_on, service_end, service_start) END
+FROM assets
+WHERE service_start IS NOT NULL;
+
+-- the backfilled tickets are already in progress, so their assets are in service
+UPDATE assets
+SET status = 'in_service'
+WHERE status = 'for_repair'
+  AND service_start IS NOT NULL
+  AND returned_on IS NULL;
+
+ALTER TABLE assets
+    DROP COLUMN service_start,
+    DROP COLUMN service_end,
+    DROP COLUMN returned_on;
+
+COMMIT;
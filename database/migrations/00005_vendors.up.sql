@@ -0,0 +1,41 @@
+BEGIN;
+
+CREATE TABLE vendors (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name         TEXT        NOT NULL,
+    contact_name TEXT,
+    email        TEXT,
+    phone_no     TEXT,
+    sla_days     INT CHECK (sla_days > 0),
+    notes        TEXT,
+    created_at   TIMESTAMPTZ DEFAULT now(),
+    updated_at   TIMESTAMPTZ,
+    archived_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_unique_vendor_name
+    ON vendors (LOWER(name))
+    WHERE archived_at IS NULL;
+
+ALTER TABLE assets
+    ADD COLUMN purchase_vendor_id UUID REFERENCES vendors(id);
+
+ALTER TABLE service_tickets
+    ADD COLUMN vendor_id UUID REFERENCES vendors(id);
+
+-- turn the free text vendors already on tickets into vendor records
+INSERT INTO vendors (name)
+SELECT DISTINCT ON (LOWER(TRIM(vendor))) TRIM(vendor)
+FROM service_tickets
+WHERE vendor IS NOT NULL
+AND TRIM(vendor) <> '';
+
+UPDATE service_tickets st
+SET vendor_id = v.id
+FROM vendors v
+WHERE LOWER(v.name) = LOWER(TRIM(st.vendor));
+
+ALTER TABLE service_tickets
+    DROP COLUMN vendor;
+
+COMMIT;
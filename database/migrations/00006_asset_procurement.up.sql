@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TYPE depreciation_method AS ENUM (
+    'straight_line',
+    'declining_balance'
+);
+
+ALTER TABLE assets
+    ADD COLUMN purchase_date  DATE,
+    ADD COLUMN purchase_price NUMERIC(12,2) CHECK (purchase_price >= 0),
+    ADD COLUMN currency       CHAR(3),
+    ADD COLUMN invoice_number TEXT;
+
+CREATE TABLE depreciation_policies (
+    asset_type         asset_type          PRIMARY KEY,
+    method             depreciation_method NOT NULL DEFAULT 'straight_line',
+    useful_life_months INT                 NOT NULL CHECK (useful_life_months > 0),
+    salvage_percent    NUMERIC(5,2)        NOT NULL DEFAULT 0 CHECK (salvage_percent BETWEEN 0 AND 100),
+    -- yearly rate for declining balance; double declining when NULL
+    declining_rate     NUMERIC(5,2)        CHECK (declining_rate > 0 AND declining_rate < 100),
+    updated_at         TIMESTAMPTZ
+);
+
+INSERT INTO depreciation_policies (asset_type, method, useful_life_months, salvage_percent)
+VALUES ('laptop', 'straight_line', 36, 10),
+       ('keyboard', 'straight_line', 24, 0),
+       ('mouse', 'straight_line', 24, 0),
+       ('mobile', 'declining_balance', 24, 10);
+
+COMMIT;
@@ -0,0 +1,109 @@
+BEGIN;
+
+CREATE TABLE asset_types (
+    name        TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_-]*$'),
+    label       TEXT        NOT NULL,
+    spec_schema JSONB       NOT NULL DEFAULT '{"type": "object"}',
+    created_at  TIMESTAMPTZ DEFAULT now(),
+    updated_at  TIMESTAMPTZ DEFAULT now(),
+    archived_at TIMESTAMPTZ
+);
+
+INSERT INTO asset_types (name, label, spec_schema)
+VALUES ('laptop', 'Laptop', '{
+            "type": "object",
+            "properties": {
+                "processor": {"type": "string"},
+                "ram": {"type": "string"},
+                "storage": {"type": "string"},
+                "operatingSystem": {"type": "string"},
+                "charger": {"type": "string"},
+                "devicePassword": {"type": "string", "minLength": 1}
+            },
+            "required": ["devicePassword"],
+            "additionalProperties": false
+        }'),
+       ('keyboard', 'Keyboard', '{
+            "type": "object",
+            "properties": {
+                "layout": {"type": "string"},
+                "connectivity": {"enum": ["wired", "wireless"]}
+            },
+            "additionalProperties": false
+        }'),
+       ('mouse', 'Mouse', '{
+            "type": "object",
+            "properties": {
+                "dpi": {"type": "integer", "minimum": 1},
+                "connectivity": {"enum": ["wired", "wireless"]}
+            },
+            "additionalProperties": false
+        }'),
+       ('mobile', 'Mobile', '{
+            "type": "object",
+            "properties": {
+                "operatingSystem": {"type": "string", "minLength": 1},
+                "ram": {"type": "string", "minLength": 1},
+                "storage": {"type": "string", "minLength": 1},
+                "charger": {"type": "string"},
+                "devicePassword": {"type": "string", "minLength": 1}
+            },
+            "required": ["operatingSystem", "ram", "storage", "devicePassword"],
+            "additionalProperties": false
+        }');
+
+ALTER TABLE assets
+    ADD COLUMN specs JSONB NOT NULL DEFAULT '{}';
+
+UPDATE assets a
+SET specs = jsonb_strip_nulls(jsonb_build_object(
+        'processor', l.processor,
+        'ram', l.ram,
+        'storage', l.storage,
+        'operatingSystem', l.operating_system,
+        'charger', l.charger,
+        'devicePassword', l.device_password))
+FROM laptops l
+WHERE l.asset_id = a.id;
+
+UPDATE assets a
+SET specs = jsonb_strip_nulls(jsonb_build_object(
+        'layout', k.layout,
+        'connectivity', k.connectivity))
+FROM keyboards k
+WHERE k.asset_id = a.id;
+
+UPDATE assets a
+SET specs = jsonb_strip_nulls(jsonb_build_object(
+        'dpi', m.dpi,
+        'connectivity', m.connectivity))
+FROM mouses m
+WHERE m.asset_id = a.id;
+
+UPDATE assets a
+SET specs = jsonb_strip_nulls(jsonb_build_object(
+        'operatingSystem', m.operating_system,
+        'ram', m.ram,
+        'storage', m.storage,
+        'charger', m.charger,
+        'devicePassword', m.device_password))
+FROM mobiles m
+WHERE m.asset_id = a.id;
+
+ALTER TABLE assets
+    ALTER COLUMN type TYPE TEXT USING type::text,
+    ADD CONSTRAINT fk_assets_type FOREIGN KEY (type) REFERENCES asset_types(name);
+
+ALTER TABLE depreciation_policies
+    ALTER COLUMN asset_type TYPE TEXT USING asset_type::text,
+    ADD CONSTRAINT fk_depreciation_policies_type FOREIGN KEY (asset_type) REFERENCES asset_types(name);
+
+DROP TABLE laptops;
+DROP TABLE keyboards;
+DROP TABLE mouses;
+DROP TABLE mobiles;
+
+DROP TYPE asset_type;
+DROP TYPE connection_type;
+
+COMMIT;
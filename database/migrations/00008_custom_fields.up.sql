@@ -0,0 +1,45 @@
+BEGIN;
+
+CREATE TYPE custom_field_entity AS ENUM (
+    'asset',
+    'user'
+);
+
+CREATE TYPE custom_field_type AS ENUM (
+    'text',
+    'number',
+    'boolean',
+    'date',
+    'select'
+);
+
+CREATE TABLE custom_field_definitions (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity         custom_field_entity NOT NULL,
+    key            TEXT                NOT NULL CHECK (key ~ '^[a-zA-Z][a-zA-Z0-9_]*$'),
+    label          TEXT                NOT NULL,
+    field_type     custom_field_type   NOT NULL,
+    required       BOOLEAN             NOT NULL DEFAULT false,
+    allowed_values TEXT[]              NOT NULL DEFAULT '{}',
+    created_at     TIMESTAMPTZ DEFAULT now(),
+    updated_at     TIMESTAMPTZ,
+    archived_at    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_unique_custom_field_key
+    ON custom_field_definitions (entity, key)
+    WHERE archived_at IS NULL;
+
+ALTER TABLE assets
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE users
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_assets_custom_fields
+    ON assets USING GIN (custom_fields);
+
+CREATE INDEX idx_users_custom_fields
+    ON users USING GIN (custom_fields);
+
+COMMIT;
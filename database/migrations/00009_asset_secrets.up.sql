@@ -0,0 +1,38 @@
+BEGIN;
+
+-- secret spec fields are encrypted with a per-secret data key, stored wrapped by a master key
+CREATE TABLE IF NOT EXISTS asset_secrets (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id    UUID NOT NULL REFERENCES assets(id),
+    field       TEXT NOT NULL,
+    ciphertext  BYTEA NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    key_id      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ,
+    UNIQUE (asset_id, field)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_secrets_key_id ON asset_secrets(key_id);
+
+CREATE TABLE IF NOT EXISTS secret_access_audit (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id    UUID NOT NULL REFERENCES assets(id),
+    field       TEXT NOT NULL,
+    accessed_by UUID NOT NULL REFERENCES users(id),
+    session_id  UUID,
+    reason      TEXT NOT NULL,
+    ip_address  TEXT,
+    accessed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_access_audit_asset ON secret_access_audit(asset_id, accessed_at);
+
+-- plaintext device passwords left in specs are sealed by the server on startup
+UPDATE asset_types
+SET spec_schema = jsonb_set(spec_schema, '{properties,devicePassword,x-secret}', 'true'),
+    updated_at = NOW()
+WHERE name IN ('laptop', 'mobile')
+AND spec_schema #> '{properties,devicePassword}' IS NOT NULL;
+
+COMMIT;
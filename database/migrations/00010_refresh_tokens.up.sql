@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TABLE user_session
+    ADD COLUMN user_agent     TEXT,
+    ADD COLUMN ip_address     TEXT,
+    ADD COLUMN last_seen_at   TIMESTAMPTZ,
+    ADD COLUMN expires_at     TIMESTAMPTZ,
+    ADD COLUMN revoked_reason TEXT;
+
+UPDATE user_session
+SET expires_at = created_at + INTERVAL '30 days'
+WHERE expires_at IS NULL;
+
+ALTER TABLE user_session
+    ALTER COLUMN expires_at SET NOT NULL;
+
+-- a session is the family of the refresh tokens rotated from its login; presenting a token
+-- that was already used revokes the whole session
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id  UUID NOT NULL REFERENCES user_session(id),
+    token_hash  TEXT NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens(session_id);
+CREATE INDEX IF NOT EXISTS idx_user_session_user ON user_session(user_id) WHERE archived_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TYPE user_token_purpose AS ENUM (
+    'password_reset',
+    'email_verification'
+);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES users(id),
+    purpose     user_token_purpose NOT NULL,
+    token_hash  TEXT NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user ON user_tokens(user_id, purpose) WHERE used_at IS NULL;
+
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- accounts from before verification existed keep their access
+UPDATE users
+SET email_verified_at = COALESCE(created_at, NOW());
+
+COMMIT;
@@ -0,0 +1,43 @@
+BEGIN;
+
+ALTER TYPE user_token_purpose ADD VALUE IF NOT EXISTS 'mfa_challenge';
+
+-- the TOTP secret is encrypted with the same envelope keys as asset secrets
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id        UUID PRIMARY KEY REFERENCES users(id),
+    ciphertext     BYTEA NOT NULL,
+    wrapped_key    BYTEA NOT NULL,
+    key_id         TEXT NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at   TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES users(id),
+    code_hash   TEXT NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user ON mfa_recovery_codes(user_id) WHERE used_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS mfa_policies (
+    role        user_role PRIMARY KEY,
+    required    BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at  TIMESTAMPTZ
+);
+
+INSERT INTO mfa_policies (role, required) VALUES
+    ('admin', TRUE),
+    ('asset-manager', TRUE),
+    ('employee', FALSE),
+    ('project-manager', FALSE),
+    ('employee-manager', FALSE)
+ON CONFLICT (role) DO NOTHING;
+
+ALTER TABLE user_session
+    ADD COLUMN mfa_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
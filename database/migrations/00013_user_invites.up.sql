@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TYPE user_token_purpose ADD VALUE IF NOT EXISTS 'invitation';
+
+-- invited users have no usable password until they accept the invitation
+ALTER TABLE users
+    ADD COLUMN invited_by_id      UUID REFERENCES users(id),
+    ADD COLUMN invite_accepted_at TIMESTAMPTZ,
+    ADD COLUMN archived_by_id     UUID REFERENCES users(id);
+
+COMMIT;
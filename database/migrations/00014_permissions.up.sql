@@ -0,0 +1,78 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS roles (
+    name         TEXT PRIMARY KEY,
+    label        TEXT NOT NULL,
+    mfa_required BOOLEAN NOT NULL DEFAULT FALSE,
+    -- builtin roles come with the application and cannot be deleted
+    builtin      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role       TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, label, mfa_required, builtin)
+SELECT p.role::text,
+       INITCAP(REPLACE(p.role::text, '-', ' ')),
+       p.required,
+       TRUE
+FROM mfa_policies p;
+
+INSERT INTO permissions (name, description) VALUES
+    ('asset.read', 'View assets, their history and book value'),
+    ('asset.create', 'Create assets'),
+    ('asset.update', 'Edit assets and change their status'),
+    ('asset.delete', 'Archive assets'),
+    ('asset.assign', 'Assign and return assets'),
+    ('asset.secret.reveal', 'Reveal encrypted asset secrets such as device passwords'),
+    ('asset_type.manage', 'Create, edit and archive asset types'),
+    ('custom_field.manage', 'Create, edit and archive custom field definitions'),
+    ('repair.manage', 'Open, update and close service tickets'),
+    ('vendor.read', 'View vendors'),
+    ('vendor.manage', 'Create, edit and archive vendors'),
+    ('depreciation.manage', 'Edit depreciation policies'),
+    ('report.export', 'View and export reports'),
+    ('user.read', 'View users and their assets'),
+    ('user.update', 'Edit custom fields of users'),
+    ('user.manage', 'Invite users, change roles, archive users and reset their MFA'),
+    ('role.manage', 'Edit roles and their permissions'),
+    ('secret.rotate', 'Rotate the encryption master key');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'asset-manager', p FROM UNNEST(ARRAY[
+    'asset.read', 'asset.create', 'asset.update', 'asset.delete', 'asset.assign',
+    'repair.manage', 'vendor.read', 'vendor.manage', 'depreciation.manage',
+    'report.export', 'user.read', 'user.update'
+]) AS p;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'project-manager', p FROM UNNEST(ARRAY['asset.read', 'user.read', 'report.export']) AS p;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'employee-manager', p FROM UNNEST(ARRAY['asset.read', 'user.read']) AS p;
+
+-- roles are rows now, the MFA policy moves onto them
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE TEXT USING role::text;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'employee';
+UPDATE users SET role = 'employee' WHERE role IS NULL;
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
+
+DROP TABLE mfa_policies;
+DROP TYPE user_role;
+
+COMMIT;
@@ -0,0 +1,45 @@
+BEGIN;
+
+ALTER TABLE asset_assignments
+    ADD COLUMN acknowledged_at TIMESTAMPTZ;
+
+CREATE TYPE issue_kind AS ENUM (
+    'lost',
+    'broken',
+    'needs_repair'
+);
+
+CREATE TYPE issue_status AS ENUM (
+    'pending',
+    'resolved',
+    'dismissed'
+);
+
+-- problems employees report with their assets, waiting for an asset manager
+CREATE TABLE IF NOT EXISTS asset_issues (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id         UUID NOT NULL REFERENCES assets(id),
+    reported_by      UUID NOT NULL REFERENCES users(id),
+    kind             issue_kind NOT NULL,
+    description      TEXT NOT NULL,
+    status           issue_status NOT NULL DEFAULT 'pending',
+    resolved_by      UUID REFERENCES users(id),
+    resolved_at      TIMESTAMPTZ,
+    resolution_notes TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pending_asset_issue
+    ON asset_issues(asset_id, kind)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_asset_issues_status ON asset_issues(status, created_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('issue.manage', 'View and resolve issues reported by employees');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'issue.manage'),
+    ('asset-manager', 'issue.manage');
+
+COMMIT;
@@ -0,0 +1,94 @@
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN manager_id UUID REFERENCES users(id);
+
+CREATE TYPE approver_kind AS ENUM (
+    'manager',
+    'role'
+);
+
+-- the approval chain new requests go through, in order of position
+CREATE TABLE IF NOT EXISTS asset_request_approval_steps (
+    position      INT PRIMARY KEY,
+    approver_kind approver_kind NOT NULL,
+    approver_role TEXT REFERENCES roles(name),
+    CHECK ((approver_kind = 'role') = (approver_role IS NOT NULL))
+);
+
+INSERT INTO asset_request_approval_steps (position, approver_kind, approver_role) VALUES
+    (1, 'manager', NULL),
+    (2, 'role', 'asset-manager');
+
+CREATE TYPE asset_request_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'fulfilled',
+    'cancelled'
+);
+
+CREATE TABLE IF NOT EXISTS asset_requests (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by       UUID NOT NULL REFERENCES users(id),
+    asset_type         TEXT NOT NULL REFERENCES asset_types(name),
+    justification      TEXT NOT NULL,
+    needed_by          DATE,
+    status             asset_request_status NOT NULL DEFAULT 'pending',
+    fulfilled_asset_id UUID REFERENCES assets(id),
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ,
+    closed_at          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_requests_status ON asset_requests(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_asset_requests_requested_by ON asset_requests(requested_by);
+
+CREATE TYPE approval_decision AS ENUM (
+    'approved',
+    'rejected',
+    'skipped'
+);
+
+-- the approval chain is copied onto each request, so changing the configuration does not move
+-- requests already in flight
+CREATE TABLE IF NOT EXISTS asset_request_approvals (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id    UUID NOT NULL REFERENCES asset_requests(id),
+    position      INT NOT NULL,
+    approver_kind approver_kind NOT NULL,
+    approver_role TEXT,
+    approver_id   UUID REFERENCES users(id),
+    decision      approval_decision,
+    decided_by    UUID REFERENCES users(id),
+    decided_at    TIMESTAMPTZ,
+    comment       TEXT,
+    UNIQUE (request_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS asset_request_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id  UUID NOT NULL REFERENCES asset_requests(id),
+    actor_id    UUID REFERENCES users(id),
+    event       TEXT NOT NULL,
+    from_status asset_request_status,
+    to_status   asset_request_status NOT NULL,
+    notes       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_request_events_request ON asset_request_events(request_id, created_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('asset_request.read', 'View every asset request'),
+    ('asset_request.fulfil', 'Fulfil approved asset requests by assigning an asset'),
+    ('asset_request.configure', 'Edit the approval chain of asset requests');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'asset_request.read'),
+    ('admin', 'asset_request.fulfil'),
+    ('admin', 'asset_request.configure'),
+    ('asset-manager', 'asset_request.read'),
+    ('asset-manager', 'asset_request.fulfil');
+
+COMMIT;
@@ -0,0 +1,58 @@
+BEGIN;
+
+CREATE TYPE offboarding_status AS ENUM (
+    'open',
+    'cleared'
+);
+
+CREATE TABLE IF NOT EXISTS offboardings (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id          UUID NOT NULL REFERENCES users(id),
+    started_by       UUID NOT NULL REFERENCES users(id),
+    last_working_day DATE,
+    notes            TEXT,
+    status           offboarding_status NOT NULL DEFAULT 'open',
+    started_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    cleared_at       TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_open_offboarding
+    ON offboardings(user_id)
+    WHERE status = 'open';
+
+CREATE TYPE return_task_status AS ENUM (
+    'pending',
+    'returned',
+    'written_off'
+);
+
+-- one task per asset the leaver held when the offboarding started
+CREATE TABLE IF NOT EXISTS offboarding_return_tasks (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    offboarding_id UUID NOT NULL REFERENCES offboardings(id),
+    asset_id       UUID NOT NULL REFERENCES assets(id),
+    assignment_id  UUID NOT NULL REFERENCES asset_assignments(id),
+    status         return_task_status NOT NULL DEFAULT 'pending',
+    resolved_by    UUID REFERENCES users(id),
+    resolved_at    TIMESTAMPTZ,
+    notes          TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_tasks_offboarding ON offboarding_return_tasks(offboarding_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pending_return_task
+    ON offboarding_return_tasks(asset_id)
+    WHERE status = 'pending';
+
+INSERT INTO permissions (name, description) VALUES
+    ('offboarding.manage', 'Offboard leavers and write off assets they cannot return'),
+    ('offboarding.read', 'View offboardings and download clearance reports');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'offboarding.manage'),
+    ('admin', 'offboarding.read'),
+    ('asset-manager', 'offboarding.manage'),
+    ('asset-manager', 'offboarding.read');
+
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+-- the bundle of assets a new joiner gets, chosen by role and employment type. A template without
+-- a role or type applies to every role or type.
+CREATE TABLE IF NOT EXISTS kit_templates (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name            TEXT NOT NULL,
+    role            TEXT REFERENCES roles(name),
+    employment_type user_type,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ,
+    archived_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_kit_template_name
+    ON kit_templates(name)
+    WHERE archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS kit_template_items (
+    template_id UUID NOT NULL REFERENCES kit_templates(id) ON DELETE CASCADE,
+    asset_type  TEXT NOT NULL REFERENCES asset_types(name),
+    quantity    INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    PRIMARY KEY (template_id, asset_type)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('kit.manage', 'Define the kit templates handed to new joiners');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'kit.manage');
+
+COMMIT;
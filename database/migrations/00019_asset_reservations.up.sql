@@ -0,0 +1,36 @@
+BEGIN;
+
+-- a reserved asset is held for a future assignment and is out of the available pool
+ALTER TYPE asset_status ADD VALUE IF NOT EXISTS 'reserved';
+
+CREATE TYPE reservation_status AS ENUM (
+    'active',
+    'converted',
+    'expired',
+    'cancelled'
+);
+
+CREATE TABLE IF NOT EXISTS asset_reservations (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id     UUID NOT NULL REFERENCES assets(id),
+    reserved_for UUID NOT NULL REFERENCES users(id),
+    reserved_by  UUID NOT NULL REFERENCES users(id),
+    starts_on    DATE NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    notes        TEXT,
+    status       reservation_status NOT NULL DEFAULT 'active',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at    TIMESTAMPTZ,
+    closed_by    UUID REFERENCES users(id),
+    CHECK (expires_at > starts_on)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_active_asset_reservation
+    ON asset_reservations(asset_id)
+    WHERE status = 'active';
+
+CREATE INDEX IF NOT EXISTS idx_asset_reservations_due
+    ON asset_reservations(starts_on)
+    WHERE status = 'active';
+
+COMMIT;
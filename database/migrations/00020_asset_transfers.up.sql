@@ -0,0 +1,46 @@
+BEGIN;
+
+ALTER TABLE asset_types
+    ADD COLUMN transfer_requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TYPE transfer_status AS ENUM (
+    'pending_recipient',
+    'pending_approval',
+    'completed',
+    'declined',
+    'rejected',
+    'cancelled'
+);
+
+-- an asset handed from its holder to another employee, without going back to the store
+CREATE TABLE IF NOT EXISTS asset_transfers (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id       UUID NOT NULL REFERENCES assets(id),
+    from_user      UUID NOT NULL REFERENCES users(id),
+    to_user        UUID NOT NULL REFERENCES users(id),
+    message        TEXT,
+    status         transfer_status NOT NULL DEFAULT 'pending_recipient',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at    TIMESTAMPTZ,
+    decided_by     UUID REFERENCES users(id),
+    decided_at     TIMESTAMPTZ,
+    decision_notes TEXT,
+    closed_at      TIMESTAMPTZ,
+    CHECK (from_user <> to_user)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_open_asset_transfer
+    ON asset_transfers(asset_id)
+    WHERE status IN ('pending_recipient', 'pending_approval');
+
+CREATE INDEX IF NOT EXISTS idx_asset_transfers_from ON asset_transfers(from_user, created_at);
+CREATE INDEX IF NOT EXISTS idx_asset_transfers_to ON asset_transfers(to_user, created_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('transfer.approve', 'Approve transfers of asset types that require it');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'transfer.approve'),
+    ('asset-manager', 'transfer.approve');
+
+COMMIT;
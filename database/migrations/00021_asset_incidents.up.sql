@@ -0,0 +1,80 @@
+BEGIN;
+
+ALTER TYPE asset_status ADD VALUE IF NOT EXISTS 'lost';
+ALTER TYPE asset_status ADD VALUE IF NOT EXISTS 'stolen';
+
+CREATE TYPE incident_kind AS ENUM (
+    'lost',
+    'stolen'
+);
+
+CREATE TYPE incident_status AS ENUM (
+    'open',
+    'recovered'
+);
+
+CREATE TABLE IF NOT EXISTS asset_incidents (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id             UUID NOT NULL REFERENCES assets(id),
+    kind                 incident_kind NOT NULL,
+    reported_by          UUID NOT NULL REFERENCES users(id),
+    occurred_on          DATE NOT NULL,
+    location             TEXT,
+    police_report_number TEXT,
+    remarks              TEXT,
+    status               incident_status NOT NULL DEFAULT 'open',
+    recovered_by         UUID REFERENCES users(id),
+    recovered_at         TIMESTAMPTZ,
+    recovery_condition   asset_condition,
+    recovery_notes       TEXT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_open_asset_incident
+    ON asset_incidents(asset_id)
+    WHERE status = 'open';
+
+-- assets written off as lost during offboarding get the incident they were missing
+INSERT INTO asset_incidents (asset_id, kind, reported_by, occurred_on, remarks, created_at)
+SELECT DISTINCT ON (t.asset_id) t.asset_id, 'lost', t.resolved_by, t.resolved_at::date, t.notes, t.resolved_at
+FROM offboarding_return_tasks t
+JOIN assets a ON a.id=t.asset_id
+WHERE t.status='written_off'
+AND a.status::text='lost'
+ORDER BY t.asset_id, t.resolved_at DESC;
+
+CREATE TYPE reminder_status AS ENUM (
+    'pending',
+    'done'
+);
+
+-- device passwords of a missing asset must be changed, reminders repeat until they are
+CREATE TABLE IF NOT EXISTS secret_rotation_reminders (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id         UUID NOT NULL REFERENCES assets(id),
+    incident_id      UUID NOT NULL REFERENCES asset_incidents(id),
+    field            TEXT NOT NULL,
+    status           reminder_status NOT NULL DEFAULT 'pending',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_reminded_at TIMESTAMPTZ,
+    completed_at     TIMESTAMPTZ,
+    completed_by     UUID REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_rotation_reminders
+    ON secret_rotation_reminders(last_reminded_at)
+    WHERE status = 'pending';
+
+INSERT INTO secret_rotation_reminders (asset_id, incident_id, field)
+SELECT i.asset_id, i.id, s.field
+FROM asset_incidents i
+JOIN asset_secrets s ON s.asset_id=i.asset_id;
+
+INSERT INTO permissions (name, description) VALUES
+    ('incident.manage', 'Report lost and stolen assets, recover them and track password rotation');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'incident.manage'),
+    ('asset-manager', 'incident.manage');
+
+COMMIT;
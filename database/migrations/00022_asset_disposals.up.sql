@@ -0,0 +1,65 @@
+BEGIN;
+
+ALTER TYPE asset_status ADD VALUE IF NOT EXISTS 'disposed';
+
+CREATE TYPE disposal_method AS ENUM (
+    'sold',
+    'donated',
+    'recycled',
+    'returned_to_client'
+);
+
+CREATE TYPE disposal_status AS ENUM (
+    'pending_approval',
+    'approved',
+    'rejected',
+    'completed',
+    'cancelled'
+);
+
+-- how an asset left the company; the book value and gain or loss are frozen on completion
+CREATE TABLE IF NOT EXISTS asset_disposals (
+    id                       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id                 UUID NOT NULL REFERENCES assets(id),
+    method                   disposal_method NOT NULL,
+    reason                   TEXT NOT NULL,
+    requested_by             UUID NOT NULL REFERENCES users(id),
+    status                   disposal_status NOT NULL DEFAULT 'pending_approval',
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_by               UUID REFERENCES users(id),
+    decided_at               TIMESTAMPTZ,
+    decision_notes           TEXT,
+    disposed_on              DATE,
+    recipient                TEXT,
+    proceeds                 NUMERIC(12,2) CHECK (proceeds >= 0),
+    currency                 CHAR(3),
+    data_wiped               BOOLEAN NOT NULL DEFAULT FALSE,
+    wipe_method              TEXT,
+    book_value               NUMERIC(12,2),
+    gain_loss                NUMERIC(12,2),
+    completed_by             UUID REFERENCES users(id),
+    completed_at             TIMESTAMPTZ,
+    certificate              BYTEA,
+    certificate_name         TEXT,
+    certificate_content_type TEXT,
+    certificate_uploaded_at  TIMESTAMPTZ,
+    CHECK (status <> 'completed' OR (disposed_on IS NOT NULL AND recipient IS NOT NULL AND data_wiped))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_open_asset_disposal
+    ON asset_disposals(asset_id)
+    WHERE status IN ('pending_approval', 'approved', 'completed');
+
+CREATE INDEX IF NOT EXISTS idx_asset_disposals_disposed_on ON asset_disposals(disposed_on)
+    WHERE status = 'completed';
+
+INSERT INTO permissions (name, description) VALUES
+    ('disposal.request', 'Request and complete asset disposals'),
+    ('disposal.approve', 'Approve or reject asset disposals');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'disposal.request'),
+    ('admin', 'disposal.approve'),
+    ('asset-manager', 'disposal.request');
+
+COMMIT;
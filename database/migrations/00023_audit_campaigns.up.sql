@@ -0,0 +1,65 @@
+BEGIN;
+
+-- a location scope covers the location and every location below it
+CREATE TYPE audit_scope AS ENUM (
+    'all',
+    'type',
+    'owner',
+    'location'
+);
+
+CREATE TYPE audit_status AS ENUM (
+    'open',
+    'closed'
+);
+
+-- a physical verification of the assets in a scope, checked against a snapshot taken on opening
+CREATE TABLE IF NOT EXISTS audit_campaigns (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name         TEXT NOT NULL,
+    scope        audit_scope NOT NULL,
+    scope_value  TEXT,
+    notes        TEXT,
+    status       audit_status NOT NULL DEFAULT 'open',
+    opened_by    UUID NOT NULL REFERENCES users(id),
+    opened_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_by    UUID REFERENCES users(id),
+    closed_at    TIMESTAMPTZ,
+    flagged_lost BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK ((scope = 'all') = (scope_value IS NULL))
+);
+
+CREATE TYPE audit_result AS ENUM (
+    'pending',
+    'found',
+    'missing',
+    'mismatched'
+);
+
+-- expected is false for assets found during the audit that were not in the snapshot
+CREATE TABLE IF NOT EXISTS audit_items (
+    campaign_id     UUID NOT NULL REFERENCES audit_campaigns(id) ON DELETE CASCADE,
+    asset_id        UUID NOT NULL REFERENCES assets(id),
+    expected        BOOLEAN NOT NULL DEFAULT TRUE,
+    expected_status asset_status NOT NULL,
+    expected_holder UUID REFERENCES users(id),
+    result          audit_result NOT NULL DEFAULT 'pending',
+    notes           TEXT,
+    checked_by      UUID REFERENCES users(id),
+    checked_at      TIMESTAMPTZ,
+    flagged_lost    BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (campaign_id, asset_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_items_result ON audit_items(campaign_id, result);
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit.manage', 'Open and close audit campaigns'),
+    ('audit.perform', 'Record audit results');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'audit.manage'),
+    ('admin', 'audit.perform'),
+    ('asset-manager', 'audit.perform');
+
+COMMIT;
@@ -0,0 +1,89 @@
+BEGIN;
+
+CREATE TYPE location_kind AS ENUM (
+    'site',
+    'floor',
+    'room',
+    'cabinet'
+);
+
+-- sites are the roots; floors sit in a site, rooms on a floor and cabinets on a floor or in a room
+CREATE TABLE IF NOT EXISTS locations (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        TEXT NOT NULL,
+    kind        location_kind NOT NULL,
+    parent_id   UUID REFERENCES locations(id),
+    address     TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    archived_at TIMESTAMPTZ,
+    CHECK ((kind = 'site') = (parent_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_location_name
+    ON locations (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), lower(name))
+    WHERE archived_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_locations_parent ON locations(parent_id);
+
+-- location_subtree returns the location with the given id and every location below it; the id
+-- is text so that filters can pass an empty string when they are not used
+CREATE OR REPLACE FUNCTION location_subtree(root TEXT) RETURNS SETOF UUID AS $$
+    WITH RECURSIVE subtree AS (
+        SELECT id FROM locations WHERE id::text = root
+        UNION ALL
+        SELECT l.id FROM locations l JOIN subtree s ON l.parent_id = s.id
+    )
+    SELECT id FROM subtree
+$$ LANGUAGE sql STABLE;
+
+-- location_site returns the site a location belongs to
+CREATE OR REPLACE FUNCTION location_site(location UUID) RETURNS UUID AS $$
+    WITH RECURSIVE ancestors AS (
+        SELECT id, parent_id FROM locations WHERE id = location
+        UNION ALL
+        SELECT l.id, l.parent_id FROM locations l JOIN ancestors a ON l.id = a.parent_id
+    )
+    SELECT id FROM ancestors WHERE parent_id IS NULL
+$$ LANGUAGE sql STABLE;
+
+-- assigned assets are with their holder and have no location
+ALTER TABLE assets
+    ADD COLUMN location_id UUID REFERENCES locations(id);
+
+CREATE INDEX IF NOT EXISTS idx_assets_location ON assets(location_id);
+
+CREATE TYPE movement_reason AS ENUM (
+    'received',
+    'moved',
+    'assigned',
+    'returned',
+    'lost',
+    'recovered',
+    'disposed'
+);
+
+CREATE TABLE IF NOT EXISTS asset_movements (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id      UUID NOT NULL REFERENCES assets(id),
+    from_location UUID REFERENCES locations(id),
+    to_location   UUID REFERENCES locations(id),
+    from_site     UUID REFERENCES locations(id),
+    to_site       UUID REFERENCES locations(id),
+    reason        movement_reason NOT NULL,
+    moved_by      UUID NOT NULL REFERENCES users(id),
+    moved_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notes         TEXT,
+    CHECK (from_location IS DISTINCT FROM to_location)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_movements_asset ON asset_movements(asset_id, moved_at);
+CREATE INDEX IF NOT EXISTS idx_asset_movements_moved_at ON asset_movements(moved_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('location.manage', 'Create, rename and archive locations');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'location.manage'),
+    ('asset-manager', 'location.manage');
+
+COMMIT;
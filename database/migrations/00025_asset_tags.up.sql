@@ -0,0 +1,26 @@
+BEGIN;
+
+-- internal asset tags printed on labels, numbered in the order assets were created
+CREATE SEQUENCE IF NOT EXISTS asset_tag_seq;
+
+ALTER TABLE assets
+    ADD COLUMN asset_tag TEXT;
+
+UPDATE assets a
+SET asset_tag = 'AST-' || lpad(t.n::text, 6, '0')
+FROM (
+    SELECT id, row_number() OVER (ORDER BY created_at, id) AS n
+    FROM assets
+) t
+WHERE t.id = a.id;
+
+SELECT setval('asset_tag_seq', (SELECT count(*) FROM assets) + 1, false);
+
+ALTER TABLE assets
+    ALTER COLUMN asset_tag SET DEFAULT 'AST-' || lpad(nextval('asset_tag_seq')::text, 6, '0'),
+    ALTER COLUMN asset_tag SET NOT NULL,
+    ADD CONSTRAINT assets_asset_tag_key UNIQUE (asset_tag);
+
+ALTER SEQUENCE asset_tag_seq OWNED BY assets.asset_tag;
+
+COMMIT;
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var body models.ForgotPasswordRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	// looked up and mailed off the request path, the response is the same for every email
+	go func(email string) {
+		if err := service.RequestPasswordReset(email); err != nil {
+			fmt.Printf("failed to send reset email: %v\n", err)
+		}
+	}(body.Email)
+	utils.RespondJSON(w, http.StatusAccepted, map[string]any{
+		"message": "if the email belongs to an account, a reset link has been sent",
+	})
+}
+
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var body models.ResetPasswordRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.ResetPassword(body.Token, body.Password); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			utils.RespondError(w, http.StatusBadRequest, err, "reset link is invalid or expired")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to reset password")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "password updated, please login again",
+	})
+}
+
+func VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var body models.VerifyEmailRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.VerifyEmail(body.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			utils.RespondError(w, http.StatusBadRequest, err, "verification link is invalid or expired")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to verify email")
+		return
+	}
+	// cached permissions still hold the unverified state
+	middleware.InvalidatePermissionCache()
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "email verified",
+	})
+}
+
+func ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	if err := service.SendEmailVerification(userCtx.UserID); err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			utils.RespondError(w, http.StatusConflict, err, "email already verified")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to send verification email")
+		return
+	}
+	utils.RespondJSON(w, http.StatusAccepted, map[string]any{
+		"message": "verification email sent",
+	})
+}
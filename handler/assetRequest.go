@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func CreateAssetRequest(w http.ResponseWriter, r *http.Request) {
+	var body models.CreateAssetRequestRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	requestID, err := service.CreateAssetRequest(userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to create asset request")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": requestID,
+	})
+}
+
+func MyAssetRequests(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	requests, err := dbHelper.ListAssetRequests(r.URL.Query().Get("status"), userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset requests")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"requests": requests,
+	})
+}
+
+func CancelAssetRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := service.CancelAssetRequest(requestID, userCtx.UserID); err != nil {
+		respondServiceError(w, err, "failed to cancel asset request")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset request cancelled",
+	})
+}
+
+// requireUnrestrictedSession refuses approvers whose session may not act for their role yet.
+func requireUnrestrictedSession(w http.ResponseWriter, r *http.Request) bool {
+	restriction, err := middleware.SessionRestriction(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to load permissions")
+		return false
+	}
+	if restriction != "" {
+		utils.RespondError(w, http.StatusForbidden, nil, restriction)
+		return false
+	}
+	return true
+}
+
+func PendingApprovals(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	if !requireUnrestrictedSession(w, r) {
+		return
+	}
+
+	requests, err := dbHelper.ListRequestsAwaitingApprover(userCtx.UserID, userCtx.Role)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset requests")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"requests": requests,
+	})
+}
+
+func ApproveAssetRequest(w http.ResponseWriter, r *http.Request) {
+	decideAssetRequest(w, r, "approved")
+}
+
+func RejectAssetRequest(w http.ResponseWriter, r *http.Request) {
+	decideAssetRequest(w, r, "rejected")
+}
+
+func decideAssetRequest(w http.ResponseWriter, r *http.Request, decision string) {
+	var body models.DecideAssetRequestRequest
+	requestID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+	if !requireUnrestrictedSession(w, r) {
+		return
+	}
+
+	if err := service.DecideAssetRequest(requestID, userCtx, decision, body.Comment); err != nil {
+		respondServiceError(w, err, "failed to decide asset request")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset request " + decision,
+	})
+}
+
+func ListAssetRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := dbHelper.ListAssetRequests(r.URL.Query().Get("status"), r.URL.Query().Get("requestedBy"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset requests")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"requests": requests,
+	})
+}
+
+func GetAssetRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	request, err := service.GetAssetRequest(requestID)
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch asset request")
+		return
+	}
+
+	// requesters and their manager approvers see the request, everyone else needs asset_request.read
+	allowed := request.RequestedBy == userCtx.UserID
+	for _, approval := range request.Approvals {
+		if approval.ApproverID != nil && *approval.ApproverID == userCtx.UserID {
+			allowed = true
+		}
+	}
+	if !allowed {
+		allowed, err = middleware.HasPermission(r, "asset_request.read")
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to load permissions")
+			return
+		}
+	}
+	if !allowed {
+		utils.RespondError(w, http.StatusForbidden, nil, "not-authorised")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"request": request,
+	})
+}
+
+func FulfilAssetRequest(w http.ResponseWriter, r *http.Request) {
+	var body models.FulfilAssetRequestRequest
+	requestID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.FulfilAssetRequest(requestID, userCtx.UserID, body.AssetID); err != nil {
+		respondServiceError(w, err, "failed to fulfil asset request")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset request fulfilled",
+	})
+}
+
+func ListApprovalSteps(w http.ResponseWriter, r *http.Request) {
+	steps, err := dbHelper.ListApprovalSteps()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch approval steps")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"steps": steps,
+	})
+}
+
+func ReplaceApprovalSteps(w http.ResponseWriter, r *http.Request) {
+	var body models.ApprovalStepsRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.ReplaceApprovalSteps(body.Steps); err != nil {
+		respondServiceError(w, err, "failed to update approval steps")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "approval steps updated",
+	})
+}
+
+func ChangeUserManager(w http.ResponseWriter, r *http.Request) {
+	var body models.ChangeManagerRequest
+	userID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.SetUserManager(userID, body.ManagerID); err != nil {
+		respondServiceError(w, err, "failed to change manager")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "manager updated",
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+// respondServiceError maps errors returned by the service layer to a response, listing the
+// allowed transitions when the asset cannot move to the requested status and the missing assets
+// when a kit is short.
+func respondServiceError(w http.ResponseWriter, err error, messageToUser string) {
+	var transitionErr *service.TransitionError
+	var shortageErr *service.KitShortageError
+	var existingDataErr *service.ExistingDataError
+	switch {
+	case errors.As(err, &transitionErr):
+		utils.RespondJSON(w, http.StatusConflict, map[string]any{
+			"statusCode":         http.StatusConflict,
+			"error":              err.Error(),
+			"message_to_user":    messageToUser,
+			"allowedTransitions": transitionErr.Allowed,
+		})
+	case errors.As(err, &shortageErr):
+		utils.RespondJSON(w, http.StatusConflict, map[string]any{
+			"statusCode":      http.StatusConflict,
+			"error":           err.Error(),
+			"message_to_user": messageToUser,
+			"shortages":       shortageErr.Shortages,
+		})
+	case errors.As(err, &existingDataErr):
+		utils.RespondJSON(w, http.StatusConflict, map[string]any{
+			"statusCode":      http.StatusConflict,
+			"error":           err.Error(),
+			"message_to_user": messageToUser,
+			"invalid":         existingDataErr.Invalid,
+		})
+	case errors.Is(err, service.ErrAssetNotFound), errors.Is(err, service.ErrTicketNotFound),
+		errors.Is(err, service.ErrSecretNotFound), errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrNotYourAsset), errors.Is(err, service.ErrIssueNotFound),
+		errors.Is(err, service.ErrAssetRequestNotFound), errors.Is(err, service.ErrOffboardingNotFound),
+		errors.Is(err, service.ErrReturnTaskNotFound), errors.Is(err, service.ErrKitTemplateNotFound),
+		errors.Is(err, service.ErrNoKitTemplate), errors.Is(err, service.ErrReservationNotFound),
+		errors.Is(err, service.ErrTransferNotFound), errors.Is(err, service.ErrReminderNotFound),
+		errors.Is(err, service.ErrDisposalNotFound), errors.Is(err, service.ErrCertificateNotFound),
+		errors.Is(err, service.ErrAuditNotFound), errors.Is(err, service.ErrUnknownSerial),
+		errors.Is(err, service.ErrLocationNotFound), errors.Is(err, service.ErrUnknownCode),
+		errors.Is(err, service.ErrNoLabels), errors.Is(err, service.ErrCustomFieldNotFound):
+		utils.RespondError(w, http.StatusNotFound, err, messageToUser)
+	case errors.Is(err, service.ErrUnknownAssetType), errors.Is(err, service.ErrInvalidSpecs),
+		errors.Is(err, service.ErrInvalidSpecSchema), errors.Is(err, service.ErrInvalidCustomFields),
+		errors.Is(err, service.ErrUnknownRole), errors.Is(err, service.ErrUnknownPermission),
+		errors.Is(err, service.ErrRoleLockout), errors.Is(err, service.ErrAssetTypeMismatch),
+		errors.Is(err, service.ErrInvalidKit), errors.Is(err, service.ErrInvalidReservation),
+		errors.Is(err, service.ErrTransferToSelf), errors.Is(err, service.ErrInvalidAuditScope),
+		errors.Is(err, service.ErrUnexpectedMissing), errors.Is(err, service.ErrInvalidLocationParent),
+		errors.Is(err, service.ErrTooManyLabels), errors.Is(err, service.ErrCertificateType):
+		utils.RespondError(w, http.StatusBadRequest, err, messageToUser)
+	case errors.Is(err, service.ErrAssetNotAssigned), errors.Is(err, service.ErrAssignmentFlow),
+		errors.Is(err, service.ErrTicketClosed), errors.Is(err, service.ErrTicketAlreadyOpen),
+		errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnrolled),
+		errors.Is(err, service.ErrMFARequired), errors.Is(err, service.ErrEmailTaken),
+		errors.Is(err, service.ErrInviteNotPending), errors.Is(err, service.ErrSelfManagement),
+		errors.Is(err, service.ErrRoleExists), errors.Is(err, service.ErrRoleInUse),
+		errors.Is(err, service.ErrBuiltinRole), errors.Is(err, service.ErrAlreadyAcknowledged),
+		errors.Is(err, service.ErrIssueAlreadyReported), errors.Is(err, service.ErrIssueAlreadyResolved),
+		errors.Is(err, service.ErrAssetRequestClosed), errors.Is(err, service.ErrNotApproved),
+		errors.Is(err, service.ErrManagerLoop), errors.Is(err, service.ErrOffboardingInProgress),
+		errors.Is(err, service.ErrReturnTaskResolved), errors.Is(err, service.ErrAssetsOutstanding),
+		errors.Is(err, service.ErrUserOffboarding), errors.Is(err, service.ErrKitTemplateExists),
+		errors.Is(err, service.ErrReservationClosed), errors.Is(err, service.ErrAssetReserved),
+		errors.Is(err, service.ErrReservationFlow), errors.Is(err, service.ErrTransferClosed),
+		errors.Is(err, service.ErrTransferPending), errors.Is(err, service.ErrAssetNotMissing),
+		errors.Is(err, service.ErrIncidentFlow), errors.Is(err, service.ErrDisposalClosed),
+		errors.Is(err, service.ErrDisposalPending), errors.Is(err, service.ErrDisposalFlow),
+		errors.Is(err, service.ErrDataNotWiped), errors.Is(err, service.ErrAuditClosed),
+		errors.Is(err, service.ErrLocationExists), errors.Is(err, service.ErrLocationInUse),
+		errors.Is(err, service.ErrAssetNotMovable), errors.Is(err, service.ErrServiceTicketFlow):
+		utils.RespondError(w, http.StatusConflict, err, messageToUser)
+	case errors.Is(err, service.ErrNotApprover), errors.Is(err, service.ErrSelfApproval),
+		errors.Is(err, service.ErrOffboardingLogin):
+		utils.RespondError(w, http.StatusForbidden, err, messageToUser)
+	case errors.Is(err, utils.ErrKeyringNotConfigured):
+		utils.RespondError(w, http.StatusServiceUnavailable, err, messageToUser)
+	default:
+		utils.RespondError(w, http.StatusBadRequest, err, messageToUser)
+	}
+}
+
+func ChangeAssetStatus(w http.ResponseWriter, r *http.Request) {
+	var body models.AssetStatusRequest
+	assetID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.ChangeAssetStatus(assetID, body.Status); err != nil {
+		respondServiceError(w, err, "failed to change asset status")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "asset status updated")
+}
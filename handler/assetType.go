@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func CreateAssetType(w http.ResponseWriter, r *http.Request) {
+	var body models.AssetTypeRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+	if _, err := service.CompileSpecSchema(body.SpecSchema); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid spec schema")
+		return
+	}
+
+	exist, err := dbHelper.IsAssetTypeExist(body.Name)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check asset type existence")
+		return
+	}
+	if exist {
+		utils.RespondError(w, http.StatusConflict, nil, "asset type exist")
+		return
+	}
+
+	if err := dbHelper.CreateAssetType(body.Name, body.Label, body.SpecSchema, body.TransferRequiresApproval); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to create asset type")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, "asset type created")
+}
+
+func ListAssetTypes(w http.ResponseWriter, r *http.Request) {
+	assetTypes, err := dbHelper.ListAssetTypes()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset types")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"assetTypes": assetTypes,
+	})
+}
+
+func GetAssetType(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	assetType, err := service.GetAssetType(name)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownAssetType) {
+			utils.RespondError(w, http.StatusNotFound, err, "asset type not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset type")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"assetType": assetType,
+	})
+}
+
+func UpdateAssetType(w http.ResponseWriter, r *http.Request) {
+	var body models.UpdateAssetTypeRequest
+	name := chi.URLParam(r, "name")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateAssetType(name, body); err != nil {
+		respondServiceError(w, err, "failed to update asset type")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "asset type updated")
+}
+
+func DeleteAssetType(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	if err := dbHelper.ArchiveAssetType(name); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to delete asset type")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "asset type deleted")
+}
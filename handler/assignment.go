@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+// parseDateQuery reads an optional YYYY-MM-DD query parameter.
+func parseDateQuery(r *http.Request, key string) (*time.Time, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// parsePeriod reads the from/to query parameters; to covers the whole day.
+func parsePeriod(r *http.Request) (*time.Time, *time.Time, error) {
+	from, err := parseDateQuery(r, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDateQuery(r, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+	if to != nil {
+		endOfDay := to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		to = &endOfDay
+	}
+	return from, to, nil
+}
+
+func AssetTimeline(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "id")
+
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid date, expected YYYY-MM-DD")
+		return
+	}
+
+	timeline, err := dbHelper.AssetTimeline(assetID, from, to)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset timeline")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"timeline": timeline,
+	})
+}
+
+func UserAssetHistory(w http.ResponseWriter, r *http.Request) {
+	respondUserAssetHistory(w, r, chi.URLParam(r, "id"))
+}
+
+func respondUserAssetHistory(w http.ResponseWriter, r *http.Request, userID string) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid date, expected YYYY-MM-DD")
+		return
+	}
+
+	history, err := dbHelper.UserAssetHistory(userID, from, to)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch asset history")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"history": history,
+	})
+}
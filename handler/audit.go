@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func OpenAudit(w http.ResponseWriter, r *http.Request) {
+	var body models.OpenAuditRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	campaignID, err := service.OpenAudit(userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to open audit")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": campaignID,
+	})
+}
+
+func ListAudits(w http.ResponseWriter, r *http.Request) {
+	// open campaigns by default, status=all lists every campaign
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "open"
+	case "all":
+		status = ""
+	}
+
+	campaigns, err := dbHelper.ListAuditCampaigns(status)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch audits")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"audits": campaigns,
+	})
+}
+
+func GetAudit(w http.ResponseWriter, r *http.Request) {
+	campaign, err := service.GetAuditCampaign(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch audit")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"audit": campaign,
+	})
+}
+
+func ListAuditItems(w http.ResponseWriter, r *http.Request) {
+	campaignID := chi.URLParam(r, "id")
+
+	if _, err := service.GetAuditCampaign(campaignID); err != nil {
+		respondServiceError(w, err, "failed to fetch audit")
+		return
+	}
+	items, err := dbHelper.ListAuditItems(campaignID, r.URL.Query().Get("result"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch audit items")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"items": items,
+	})
+}
+
+func RecordAuditResults(w http.ResponseWriter, r *http.Request) {
+	var body models.RecordAuditResultsRequest
+	campaignID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.RecordAuditResults(campaignID, userCtx.UserID, body.Results); err != nil {
+		respondServiceError(w, err, "failed to record audit results")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "audit results recorded",
+	})
+}
+
+func CloseAudit(w http.ResponseWriter, r *http.Request) {
+	var body models.CloseAuditRequest
+	campaignID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+
+	report, err := service.CloseAudit(campaignID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to close audit")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"report": report,
+	})
+}
+
+func AuditReport(w http.ResponseWriter, r *http.Request) {
+	report, err := service.AuditReport(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to build audit report")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"report": report,
+	})
+}
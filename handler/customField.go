@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	var body models.CustomFieldDefinitionRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	exist, err := dbHelper.IsCustomFieldExist(body.Entity, body.Key)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check custom field existence")
+		return
+	}
+	if exist {
+		utils.RespondError(w, http.StatusConflict, nil, "custom field exist")
+		return
+	}
+
+	fieldID, err := dbHelper.CreateCustomField(body)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to create custom field")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]string{
+		"id": fieldID,
+	})
+}
+
+func ListCustomFields(w http.ResponseWriter, r *http.Request) {
+	entity := r.URL.Query().Get("entity")
+
+	definitions, err := dbHelper.ListCustomFields(entity)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch custom fields")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"customFields": definitions,
+	})
+}
+
+func UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+	var body models.UpdateCustomFieldDefinitionRequest
+	fieldID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateCustomField(fieldID, body); err != nil {
+		respondServiceError(w, err, "failed to update custom field")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "custom field updated")
+}
+
+func DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	fieldID := chi.URLParam(r, "id")
+
+	if err := dbHelper.ArchiveCustomField(fieldID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to delete custom field")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "custom field deleted")
+}
+
+func UpdateUserCustomFields(w http.ResponseWriter, r *http.Request) {
+	var body models.UserCustomFieldsRequest
+	userID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+
+	customFields, err := service.ValidateCustomFields("user", body.CustomFields)
+	if err != nil {
+		respondServiceError(w, err, "invalid custom fields")
+		return
+	}
+	if err := dbHelper.UpdateUserCustomFields(userID, customFields); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to update custom fields")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "custom fields updated")
+}
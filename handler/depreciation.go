@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+// parseAsOf reads the asOf query parameter, defaulting to today.
+func parseAsOf(r *http.Request) (time.Time, error) {
+	asOf, err := parseDateQuery(r, "asOf")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if asOf == nil {
+		return time.Now().UTC().Truncate(24 * time.Hour), nil
+	}
+	return *asOf, nil
+}
+
+func AssetBookValue(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "id")
+
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid asOf, expected YYYY-MM-DD")
+		return
+	}
+
+	value, err := service.AssetBookValue(assetID, asOf)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			utils.RespondError(w, http.StatusNotFound, err, "asset not found")
+		case errors.Is(err, service.ErrAssetNotValued):
+			utils.RespondError(w, http.StatusUnprocessableEntity, err, "asset cannot be valued")
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to compute book value")
+		}
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"bookValue": value,
+	})
+}
+
+func DepreciationReport(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid asOf, expected YYYY-MM-DD")
+		return
+	}
+
+	report, err := service.DepreciationReport(asOf)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to build depreciation report")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"report": report,
+	})
+}
+
+func ListDepreciationPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := dbHelper.ListDepreciationPolicies()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch depreciation policies")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"policies": policies,
+	})
+}
+
+func UpdateDepreciationPolicy(w http.ResponseWriter, r *http.Request) {
+	var body models.DepreciationPolicyRequest
+	assetType := chi.URLParam(r, "type")
+
+	if _, err := service.GetAssetType(assetType); err != nil {
+		respondServiceError(w, err, "invalid asset type")
+		return
+	}
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := dbHelper.UpsertDepreciationPolicy(assetType, body); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to update depreciation policy")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "depreciation policy updated")
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func RequestDisposal(w http.ResponseWriter, r *http.Request) {
+	var body models.RequestDisposalRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	disposalID, err := service.RequestDisposal(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to request disposal")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": disposalID,
+	})
+}
+
+func ListDisposals(w http.ResponseWriter, r *http.Request) {
+	// disposals waiting for approval by default, status=all lists every disposal
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "pending_approval"
+	case "all":
+		status = ""
+	}
+
+	disposals, err := dbHelper.ListDisposals(status, r.URL.Query().Get("assetId"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch disposals")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"disposals": disposals,
+	})
+}
+
+func GetDisposal(w http.ResponseWriter, r *http.Request) {
+	disposal, err := service.GetDisposal(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch disposal")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"disposal": disposal,
+	})
+}
+
+func ApproveDisposal(w http.ResponseWriter, r *http.Request) {
+	decideDisposal(w, r, true)
+}
+
+func RejectDisposal(w http.ResponseWriter, r *http.Request) {
+	decideDisposal(w, r, false)
+}
+
+func decideDisposal(w http.ResponseWriter, r *http.Request, approve bool) {
+	var body models.DecideDisposalRequest
+	disposalID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.DecideDisposal(disposalID, userCtx.UserID, approve, body.Notes); err != nil {
+		respondServiceError(w, err, "failed to decide disposal")
+		return
+	}
+	message := "disposal rejected"
+	if approve {
+		message = "disposal approved"
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": message,
+	})
+}
+
+func CancelDisposal(w http.ResponseWriter, r *http.Request) {
+	if err := service.CancelDisposal(chi.URLParam(r, "id")); err != nil {
+		respondServiceError(w, err, "failed to cancel disposal")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "disposal cancelled",
+	})
+}
+
+func CompleteDisposal(w http.ResponseWriter, r *http.Request) {
+	var body models.CompleteDisposalRequest
+	disposalID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.CompleteDisposal(disposalID, userCtx.UserID, body); err != nil {
+		respondServiceError(w, err, "failed to complete disposal")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset disposed",
+	})
+}
+
+// UploadDisposalCertificate stores the request body as the certificate of a disposal, named by
+// the filename query parameter. The file type is sniffed from the content.
+func UploadDisposalCertificate(w http.ResponseWriter, r *http.Request) {
+	disposalID := chi.URLParam(r, "id")
+
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, service.MaxCertificateSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.RespondError(w, http.StatusRequestEntityTooLarge, err, "certificate is too large")
+			return
+		}
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to read certificate")
+		return
+	}
+	if len(content) == 0 {
+		utils.RespondError(w, http.StatusBadRequest, errors.New("empty body"), "certificate is empty")
+		return
+	}
+	name := path.Base(r.URL.Query().Get("filename"))
+	if name == "." || name == "/" {
+		name = "certificate"
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+
+	if err := service.SaveDisposalCertificate(disposalID, name, contentType, content); err != nil {
+		respondServiceError(w, err, "failed to save certificate")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "certificate saved",
+	})
+}
+
+func DownloadDisposalCertificate(w http.ResponseWriter, r *http.Request) {
+	certificate, err := service.GetDisposalCertificate(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch certificate")
+		return
+	}
+	contentType := certificate.ContentType
+	if !slices.Contains(service.CertificateContentTypes, contentType) {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename=%q`, certificate.Name))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(certificate.Content); err != nil {
+		fmt.Printf("failed to write certificate: %v\n", err)
+	}
+}
+
+func DisposalReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid from or to, expected YYYY-MM-DD")
+		return
+	}
+
+	report, err := service.DisposalReport(from, to)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to build disposal report")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"report": report,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -12,6 +13,7 @@ import (
 	"github.com/nikhilpratapgit/storex/database/dbHelper"
 	"github.com/nikhilpratapgit/storex/middleware"
 	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
 	"github.com/nikhilpratapgit/storex/utils"
 )
 
@@ -19,8 +21,6 @@ var validate = validator.New()
 
 func RegisterUser(w http.ResponseWriter, r *http.Request) {
 	var registerUser models.RegisterUser
-	var userID string
-	var sessionID string
 	if parseErr := utils.ParseBody(r.Body, &registerUser); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse body")
 		return
@@ -41,39 +41,35 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusBadRequest, nil, "user exist")
 		return
 	}
+	customFields, err := service.ValidateCustomFields("user", registerUser.CustomFields)
+	if err != nil {
+		respondServiceError(w, err, "invalid custom fields")
+		return
+	}
 	hashPassword, err := utils.HashPassword(registerUser.Password)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed while hashing password")
 		return
 	}
 
-	TxErr := database.Tx(func(tx *sqlx.Tx) error {
-		userID, saveErr := dbHelper.CreateUser(registerUser.Name, registerUser.Email, registerUser.Role, registerUser.Type, registerUser.PhoneNumber, hashPassword)
-		if saveErr != nil {
-			utils.RespondError(w, http.StatusNotFound, saveErr, "failed to create user")
-			return err
-		}
-		//SESSION
-		sessionID, err = dbHelper.CreateUserSession(userID)
-		if err != nil {
-			utils.RespondError(w, http.StatusInternalServerError, err, "failed to create user session")
-			return err
-		}
-		return nil
-	})
-	if TxErr != nil {
+	// self registered users are employees, other roles are granted by an admin
+	userID, err := dbHelper.CreateUser(registerUser.Name, registerUser.Email, service.DefaultRole, registerUser.Type, registerUser.PhoneNumber, hashPassword, customFields)
+	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create user")
+		return
 	}
 
-	token, err := utils.GenerateJWT(userID, sessionID, registerUser.Role)
+	// the user can log in before verifying, but permissions are only granted once verified
+	tokens, err := service.StartSession(userID, service.DefaultRole, r.UserAgent(), r.RemoteAddr, false)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate token")
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create user session")
 		return
 	}
-
-	utils.RespondJSON(w, http.StatusCreated, struct {
-		Token string `json:"token"`
-	}{Token: token})
+	if err := service.SendEmailVerification(userID); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "account created but the verification email could not be sent, log in to resend it")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, tokens)
 }
 
 func LoginUser(w http.ResponseWriter, r *http.Request) {
@@ -98,20 +94,31 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusUnauthorized, err, "invalid credentials")
 		return
 	}
-	sessionID, err := dbHelper.CreateUserSession(user.ID)
+	mfaEnabled, err := dbHelper.IsMFAEnabled(user.ID)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create user session")
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check two-factor authentication")
+		return
+	}
+	if mfaEnabled {
+		challenge, err := service.BeginMFAChallenge(user.ID)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to start two-factor login")
+			return
+		}
+		utils.RespondJSON(w, http.StatusOK, challenge)
 		return
 	}
 
-	token, err := utils.GenerateJWT(user.ID, sessionID, user.Role)
+	tokens, err := service.StartSession(user.ID, user.Role, r.UserAgent(), r.RemoteAddr, false)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "failed to generate token")
+		if errors.Is(err, service.ErrOffboardingLogin) {
+			utils.RespondError(w, http.StatusForbidden, err, "account is being offboarded")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create user session")
 		return
 	}
-	utils.RespondJSON(w, http.StatusCreated, struct {
-		Token string `json:"token"`
-	}{Token: token})
+	utils.RespondJSON(w, http.StatusCreated, tokens)
 }
 func Logout(w http.ResponseWriter, r *http.Request) {
 	userCtx := middleware.UserContext(r)
@@ -128,6 +135,7 @@ func Logout(w http.ResponseWriter, r *http.Request) {
 
 func CreateAsset(w http.ResponseWriter, r *http.Request) {
 	var assetRequest models.Asset
+	userCtx := middleware.UserContext(r)
 
 	if parseErr := utils.ParseBody(r.Body, &assetRequest); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse body")
@@ -143,35 +151,44 @@ func CreateAsset(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusBadRequest, nil, "invalid warranty range")
 		return
 	}
+	if err := service.CheckInitialStatus(assetRequest.Status); err != nil {
+		respondServiceError(w, err, "invalid asset status")
+		return
+	}
+
+	specs, err := service.PrepareSpecs(assetRequest.AssetType, "", assetRequest.Specs)
+	if err != nil {
+		respondServiceError(w, err, "invalid asset specs")
+		return
+	}
+	assetRequest.Specs = specs.Specs
+
+	if assetRequest.LocationID != nil {
+		if _, err := service.GetLocation(*assetRequest.LocationID); err != nil {
+			respondServiceError(w, err, "invalid location")
+			return
+		}
+	}
+
+	customFields, err := service.ValidateCustomFields("asset", assetRequest.CustomFields)
+	if err != nil {
+		respondServiceError(w, err, "invalid custom fields")
+		return
+	}
+	assetRequest.CustomFields = customFields
 
 	Txerr := database.Tx(func(tx *sqlx.Tx) error {
 		assetID, err := dbHelper.CreateAsset(tx, assetRequest)
 		if err != nil {
 			return fmt.Errorf("failed to create asset: %w", err)
 		}
-
-		switch assetRequest.AssetType {
-		case "laptop":
-			if err := dbHelper.CreateLaptop(tx, assetID, assetRequest.Laptop); err != nil {
-				return fmt.Errorf("failed to create laptop: %w", err)
-			}
-		case "keyboard":
-			if err := dbHelper.CreateKeyboard(tx, assetID, assetRequest.Keyboard); err != nil {
-				return fmt.Errorf("failed to create keyboard: %w", err)
-			}
-		case "mouse":
-			if err := dbHelper.CreateMouse(tx, assetID, assetRequest.Mouse); err != nil {
-				return fmt.Errorf("failed to create mouse: %w", err)
-			}
-		case "mobile":
-			if err := dbHelper.CreateMobile(tx, assetID, assetRequest.Mobile); err != nil {
-				return fmt.Errorf("failed to create mobile: %w", err)
-			}
-		default:
-			return fmt.Errorf("unsupported asset type: %s", assetRequest.AssetType)
+		if err := specs.StoreSecrets(tx, assetID); err != nil {
+			return err
 		}
-
-		return nil
+		if assetRequest.LocationID == nil {
+			return nil
+		}
+		return service.PlaceNewAsset(tx, assetID, *assetRequest.LocationID, userCtx.UserID)
 	})
 
 	if Txerr != nil {
@@ -191,6 +208,7 @@ func ShowAssets(w http.ResponseWriter, r *http.Request) {
 	brandStr := r.URL.Query().Get("brand")
 	modelStr := r.URL.Query().Get("model")
 	serialNumberStr := r.URL.Query().Get("serialNumber")
+	locationID := r.URL.Query().Get("locationId")
 
 	pageStr := r.URL.Query().Get("page")
 	limitStr := r.URL.Query().Get("limit")
@@ -205,13 +223,19 @@ func ShowAssets(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * limit
 
-	Assets, err := dbHelper.ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberStr, limit, offset)
+	customFieldFilter, err := service.CustomFieldFilter("asset", r.URL.Query())
+	if err != nil {
+		respondServiceError(w, err, "invalid custom field filter")
+		return
+	}
+
+	Assets, err := dbHelper.ShowAssets(typeStr, statusStr, ownerStr, brandStr, modelStr, serialNumberStr, locationID, customFieldFilter, limit, offset)
 	if err != nil {
 		//log.Println(err)
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch assets")
 		return
 	}
-	Summary, err := dbHelper.DashboardData()
+	Summary, err := dbHelper.DashboardData(locationID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch dashboard data")
 		return
@@ -241,32 +265,37 @@ func AssignedAssets(w http.ResponseWriter, r *http.Request) {
 	userCtx := middleware.UserContext(r)
 	userID := userCtx.UserID
 
-	err := dbHelper.AssignedAssets(assetID, userID, assignedAsset.AssignedTo)
+	err := service.AssignAsset(assetID, userID, assignedAsset.AssignedTo)
 	if err != nil {
-		utils.RespondError(w, http.StatusBadRequest, err, "failed to assigned assets")
+		respondServiceError(w, err, "failed to assigned assets")
+		return
 	}
 	utils.RespondJSON(w, http.StatusOK, "successfully assigned")
 }
-func ServiceAssets(w http.ResponseWriter, r *http.Request) {
-	var serviceAsset models.ServiceAsset
+
+func ReturnAsset(w http.ResponseWriter, r *http.Request) {
+	var returnAsset models.ReturnAsset
 	assetID := chi.URLParam(r, "id")
 
-	if err := utils.ParseBody(r.Body, &serviceAsset); err != nil {
-		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+	if parseErr := utils.ParseBody(r.Body, &returnAsset); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse body")
 		return
 	}
-	validateErr := validate.Struct(&serviceAsset)
+	validateErr := validate.Struct(&returnAsset)
 	if validateErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
 		return
 	}
 
-	err := dbHelper.ServiceAssets(assetID, serviceAsset.ServiceStart, serviceAsset.ServiceEnd, serviceAsset.ReturnedOn)
+	userCtx := middleware.UserContext(r)
+	userID := userCtx.UserID
+
+	err := service.ReturnAsset(assetID, userID, returnAsset.Condition, returnAsset.Notes, returnAsset.LocationID)
 	if err != nil {
-		utils.RespondError(w, http.StatusBadRequest, err, "asset service failed")
+		respondServiceError(w, err, "failed to return asset")
 		return
 	}
-	utils.RespondJSON(w, http.StatusOK, "asset service complete")
+	utils.RespondJSON(w, http.StatusOK, "asset returned successfully")
 }
 func DeleteAsset(w http.ResponseWriter, r *http.Request) {
 	var deleteAsset models.DeleteAsset
@@ -299,7 +328,13 @@ func GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	userType := query.Get("type")
 	assetStatus := query.Get("status")
 
-	userDetails, err := dbHelper.GetUserInfo(name, role, userType, assetStatus)
+	customFieldFilter, err := service.CustomFieldFilter("user", query)
+	if err != nil {
+		respondServiceError(w, err, "invalid custom field filter")
+		return
+	}
+
+	userDetails, err := dbHelper.GetUserInfo(name, role, userType, assetStatus, customFieldFilter)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch users")
 		return
@@ -313,6 +348,19 @@ func FetchUser(w http.ResponseWriter, r *http.Request) {
 	//query := r.URL.Query()
 	userID := chi.URLParam(r, "id")
 
+	// everyone can see themselves, other users need user.read
+	if userID != middleware.UserContext(r).UserID {
+		allowed, err := middleware.HasPermission(r, "user.read")
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to load permissions")
+			return
+		}
+		if !allowed {
+			utils.RespondError(w, http.StatusForbidden, nil, "not-authorised")
+			return
+		}
+	}
+
 	userDetails, err := dbHelper.FetchUser(userID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch users")
@@ -346,38 +394,25 @@ func UpdateAsset(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusBadRequest, nil, "invalid warranty range")
 		return
 	}
+	specs, err := service.PrepareSpecs(body.Type, assetId, body.Specs)
+	if err != nil {
+		respondServiceError(w, err, "invalid asset specs")
+		return
+	}
+	body.Specs = specs.Specs
+
+	customFields, err := service.ValidateCustomFields("asset", body.CustomFields)
+	if err != nil {
+		respondServiceError(w, err, "invalid custom fields")
+		return
+	}
+	body.CustomFields = customFields
+
 	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		err := dbHelper.UpdateAsset(tx, assetId, body.Brand, body.Model, body.SerialNo, body.Type, body.Owner, body.WarrantyStart, body.WarrantyEnd)
-		if err != nil {
+		if err := dbHelper.UpdateAsset(tx, assetId, body); err != nil {
 			return err
 		}
-		switch body.Type {
-
-		case "laptop":
-			if body.Laptop == nil {
-				return fmt.Errorf("laptop details required")
-			}
-			return dbHelper.UpdateLaptop(tx, assetId, body.Laptop)
-
-		case "mouse":
-			if body.Mouse == nil {
-				return fmt.Errorf("mouse details required")
-			}
-			return dbHelper.UpdateMouse(tx, assetId, body.Mouse)
-		case "keyboard":
-			if body.Keyboard == nil {
-				return fmt.Errorf("keyboard details required")
-			}
-			return dbHelper.UpdateKeyboard(tx, assetId, body.Keyboard)
-		case "mobile":
-			if body.Mobile == nil {
-				return fmt.Errorf("mobile details required")
-			}
-			return dbHelper.UpdateMobile(tx, assetId, body.Mobile)
-
-		default:
-			return fmt.Errorf("unsupported asset type")
-		}
+		return specs.StoreSecrets(tx, assetId)
 	})
 	if txErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, txErr, "fail to update asset")
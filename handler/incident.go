@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ReportIncident(w http.ResponseWriter, r *http.Request) {
+	var body models.ReportIncidentRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	incidentID, err := service.ReportIncident(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to report incident")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": incidentID,
+	})
+}
+
+func RecoverAsset(w http.ResponseWriter, r *http.Request) {
+	var body models.RecoverAssetRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.RecoverAsset(assetID, userCtx.UserID, body); err != nil {
+		respondServiceError(w, err, "failed to recover asset")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset recovered",
+	})
+}
+
+func ListIncidents(w http.ResponseWriter, r *http.Request) {
+	// open incidents by default, status=all lists every incident
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "open"
+	case "all":
+		status = ""
+	}
+
+	incidents, err := dbHelper.ListIncidents(status, r.URL.Query().Get("assetId"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch incidents")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"incidents": incidents,
+	})
+}
+
+func AssetIncidents(w http.ResponseWriter, r *http.Request) {
+	incidents, err := dbHelper.ListIncidents("", chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch incidents")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"incidents": incidents,
+	})
+}
+
+func ListRotationReminders(w http.ResponseWriter, r *http.Request) {
+	// pending reminders by default, status=all lists every reminder
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "pending"
+	case "all":
+		status = ""
+	}
+
+	reminders, err := dbHelper.ListRotationReminders(status)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch rotation reminders")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"reminders": reminders,
+	})
+}
+
+func CompleteRotationReminder(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	if err := service.CompleteRotationReminder(chi.URLParam(r, "id"), userCtx.UserID); err != nil {
+		respondServiceError(w, err, "failed to complete rotation reminder")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "rotation reminder completed",
+	})
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ListKitTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := dbHelper.ListKitTemplates()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch kit templates")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"templates": templates,
+	})
+}
+
+func GetKitTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := service.GetKitTemplate(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch kit template")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"template": template,
+	})
+}
+
+func CreateKitTemplate(w http.ResponseWriter, r *http.Request) {
+	var body models.KitTemplateRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	templateID, err := service.CreateKitTemplate(body)
+	if err != nil {
+		respondServiceError(w, err, "failed to create kit template")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": templateID,
+	})
+}
+
+func UpdateKitTemplate(w http.ResponseWriter, r *http.Request) {
+	var body models.KitTemplateRequest
+	templateID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateKitTemplate(templateID, body); err != nil {
+		respondServiceError(w, err, "failed to update kit template")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "kit template updated",
+	})
+}
+
+func DeleteKitTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := service.DeleteKitTemplate(chi.URLParam(r, "id")); err != nil {
+		respondServiceError(w, err, "failed to delete kit template")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "kit template deleted",
+	})
+}
+
+func AssignKit(w http.ResponseWriter, r *http.Request) {
+	var body models.AssignKitRequest
+	userID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	assets, err := service.AssignKit(userID, body.TemplateID, userCtx.UserID)
+	if err != nil {
+		respondServiceError(w, err, "failed to assign kit")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"assets": assets,
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+// AssetLabel serves the label of an asset as PNG; kind=qr or kind=code128 serve one code only.
+func AssetLabel(w http.ResponseWriter, r *http.Request) {
+	image, err := service.AssetLabelPNG(chi.URLParam(r, "id"), r.URL.Query().Get("kind"))
+	if err != nil {
+		respondServiceError(w, err, "failed to render label")
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(image); err != nil {
+		fmt.Printf("failed to write label: %v\n", err)
+	}
+}
+
+// LabelSheet serves a PDF of label sheets for the assets matching the filters, or for the
+// comma separated ids.
+func LabelSheet(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.LabelFilter{
+		AssetIDs:   make([]string, 0),
+		Type:       query.Get("type"),
+		Status:     query.Get("status"),
+		Owner:      query.Get("owner"),
+		LocationID: query.Get("locationId"),
+	}
+	for _, id := range strings.Split(query.Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			if err := validate.Var(id, "uuid"); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err, "invalid asset id")
+				return
+			}
+			filter.AssetIDs = append(filter.AssetIDs, id)
+		}
+	}
+
+	sheet, err := service.LabelSheetPDF(filter)
+	if err != nil {
+		respondServiceError(w, err, "failed to render labels")
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="asset-labels.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(sheet); err != nil {
+		fmt.Printf("failed to write labels: %v\n", err)
+	}
+}
+
+func ScanAsset(w http.ResponseWriter, r *http.Request) {
+	asset, err := service.ScanAsset(chi.URLParam(r, "code"))
+	if err != nil {
+		respondServiceError(w, err, "failed to resolve code")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"asset": asset,
+	})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ListLocations(w http.ResponseWriter, r *http.Request) {
+	locations, err := dbHelper.ListLocations(r.URL.Query().Get("rootId"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch locations")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"locations": locations,
+	})
+}
+
+func GetLocation(w http.ResponseWriter, r *http.Request) {
+	location, err := service.GetLocation(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch location")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"location": location,
+	})
+}
+
+func CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var body models.CreateLocationRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	locationID, err := service.CreateLocation(body)
+	if err != nil {
+		respondServiceError(w, err, "failed to create location")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": locationID,
+	})
+}
+
+func UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	var body models.UpdateLocationRequest
+	locationID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateLocation(locationID, body); err != nil {
+		respondServiceError(w, err, "failed to update location")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "location updated",
+	})
+}
+
+func ArchiveLocation(w http.ResponseWriter, r *http.Request) {
+	if err := service.ArchiveLocation(chi.URLParam(r, "id")); err != nil {
+		respondServiceError(w, err, "failed to archive location")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "location archived",
+	})
+}
+
+func MoveAsset(w http.ResponseWriter, r *http.Request) {
+	var body models.MoveAssetRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.MoveAsset(assetID, userCtx.UserID, body); err != nil {
+		respondServiceError(w, err, "failed to move asset")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset moved",
+	})
+}
+
+func AssetMovements(w http.ResponseWriter, r *http.Request) {
+	movements, err := dbHelper.ListAssetMovements(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch movements")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"movements": movements,
+	})
+}
+
+func ListMovements(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid from or to, expected YYYY-MM-DD")
+		return
+	}
+	crossSite := r.URL.Query().Get("crossSite") == "true"
+
+	movements, err := dbHelper.ListMovements(from, to, r.URL.Query().Get("locationId"), crossSite)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch movements")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"movements": movements,
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var body models.MFALoginRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	tokens, err := service.CompleteMFALogin(body.MFAToken, body.Code, body.RecoveryCode, r.UserAgent(), r.RemoteAddr)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMFACode) || errors.Is(err, service.ErrInvalidUserToken) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "invalid code, please login again")
+			return
+		}
+		respondServiceError(w, err, "failed to complete login")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, tokens)
+}
+
+func EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	enrollment, err := service.EnrollMFA(userCtx.UserID)
+	if err != nil {
+		respondServiceError(w, err, "failed to enroll two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, enrollment)
+}
+
+func ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	var body models.MFACodeRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	codes, err := service.ConfirmMFA(userCtx.UserID, userCtx.SessionID, body.Code)
+	if err != nil {
+		respondServiceError(w, err, "failed to confirm two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message":       "two-factor authentication enabled, store the recovery codes safely",
+		"recoveryCodes": codes,
+	})
+}
+
+func RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var body models.MFACodeRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	codes, err := service.RegenerateRecoveryCodes(userCtx.UserID, body.Code)
+	if err != nil {
+		respondServiceError(w, err, "failed to regenerate recovery codes")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"recoveryCodes": codes,
+	})
+}
+
+func DisableMFA(w http.ResponseWriter, r *http.Request) {
+	var body models.MFACodeRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.DisableMFA(userCtx.UserID, userCtx.Role, body.Code); err != nil {
+		respondServiceError(w, err, "failed to disable two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "two-factor authentication disabled",
+	})
+}
+
+func ResetUserMFA(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+
+	if err := service.ResetUserMFA(userID); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to reset two-factor authentication")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "two-factor authentication reset",
+	})
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func StartOffboarding(w http.ResponseWriter, r *http.Request) {
+	var body models.StartOffboardingRequest
+	userID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	offboardingID, err := service.StartOffboarding(userCtx.UserID, userID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to start offboarding")
+		return
+	}
+	offboarding, err := service.GetOffboarding(offboardingID)
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch offboarding")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"offboarding": offboarding,
+	})
+}
+
+func ListOffboardings(w http.ResponseWriter, r *http.Request) {
+	offboardings, err := dbHelper.ListOffboardings(r.URL.Query().Get("status"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch offboardings")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"offboardings": offboardings,
+	})
+}
+
+func GetOffboarding(w http.ResponseWriter, r *http.Request) {
+	offboarding, err := service.GetOffboarding(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch offboarding")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"offboarding": offboarding,
+	})
+}
+
+func WriteOffReturnTask(w http.ResponseWriter, r *http.Request) {
+	var body models.WriteOffRequest
+	offboardingID := chi.URLParam(r, "id")
+	taskID := chi.URLParam(r, "taskId")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.WriteOffAsset(offboardingID, taskID, userCtx.UserID, body.Notes); err != nil {
+		respondServiceError(w, err, "failed to write off asset")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "asset written off as lost",
+	})
+}
+
+// ClearanceReport downloads the offboarding of a leaver as CSV, a summary of the offboarding
+// followed by one row per asset they had to return.
+func ClearanceReport(w http.ResponseWriter, r *http.Request) {
+	offboarding, err := service.GetOffboarding(chi.URLParam(r, "id"))
+	if err != nil {
+		respondServiceError(w, err, "failed to fetch offboarding")
+		return
+	}
+
+	rows := [][]string{
+		{"employee", offboarding.UserName},
+		{"email", offboarding.UserEmail},
+		{"last working day", formatDate(offboarding.LastWorkingDay)},
+		{"offboarding started", offboarding.StartedAt.Format(time.RFC3339)},
+		{"status", offboarding.Status},
+		{"cleared at", formatTime(offboarding.ClearedAt)},
+		{},
+		{"serial number", "type", "brand", "model", "assigned from", "outcome", "return condition", "resolved at", "resolved by", "notes"},
+	}
+	for _, task := range offboarding.Tasks {
+		rows = append(rows, []string{
+			task.SerialNumber,
+			task.AssetType,
+			task.Brand,
+			task.Model,
+			task.AssignedFrom.Format(time.RFC3339),
+			task.Status,
+			stringOrEmpty(task.ReturnCondition),
+			formatTime(task.ResolvedAt),
+			stringOrEmpty(task.ResolvedByName),
+			stringOrEmpty(task.Notes),
+		})
+	}
+	// the report is built before anything is sent, so a failure can still be reported as an error
+	var report bytes.Buffer
+	if err := csv.NewWriter(&report).WriteAll(rows); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to write clearance report")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="clearance-%s.csv"`, offboarding.ID))
+	if _, err := w.Write(report.Bytes()); err != nil {
+		fmt.Printf("failed to send clearance report: %v\n", err)
+	}
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
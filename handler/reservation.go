@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ReserveAsset(w http.ResponseWriter, r *http.Request) {
+	var body models.ReserveAssetRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	reservationID, err := service.ReserveAsset(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to reserve asset")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": reservationID,
+	})
+}
+
+func ListReservations(w http.ResponseWriter, r *http.Request) {
+	// active reservations by default, status=all lists every reservation
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "active"
+	case "all":
+		status = ""
+	}
+
+	reservations, err := dbHelper.ListReservations(status, r.URL.Query().Get("userId"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch reservations")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"reservations": reservations,
+	})
+}
+
+func MyReservations(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	reservations, err := dbHelper.ListReservations("active", userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch reservations")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"reservations": reservations,
+	})
+}
+
+func CancelReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := service.CancelReservation(reservationID, userCtx.UserID); err != nil {
+		respondServiceError(w, err, "failed to cancel reservation")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "reservation cancelled",
+	})
+}
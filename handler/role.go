@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func ListPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := dbHelper.ListPermissions()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch permissions")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"permissions": permissions,
+	})
+}
+
+func ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := dbHelper.ListRoles()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch roles")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"roles": roles,
+	})
+}
+
+func CreateRole(w http.ResponseWriter, r *http.Request) {
+	var body models.RoleRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.CreateRole(body); err != nil {
+		respondServiceError(w, err, "failed to create role")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"name": body.Name,
+	})
+}
+
+func UpdateRole(w http.ResponseWriter, r *http.Request) {
+	var body models.UpdateRoleRequest
+	name := chi.URLParam(r, "name")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateRole(name, body); err != nil {
+		respondServiceError(w, err, "failed to update role")
+		return
+	}
+	middleware.InvalidatePermissionCache()
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "role updated",
+	})
+}
+
+func DeleteRole(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	if err := service.DeleteRole(name); err != nil {
+		respondServiceError(w, err, "failed to delete role")
+		return
+	}
+	middleware.InvalidatePermissionCache()
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "role deleted",
+	})
+}
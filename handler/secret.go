@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func RevealAssetPassword(w http.ResponseWriter, r *http.Request) {
+	var body models.RevealSecretRequest
+	assetID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+	if body.Field == "" {
+		body.Field = service.DefaultSecretField
+	}
+
+	userCtx := middleware.UserContext(r)
+	value, err := service.RevealSecret(assetID, body.Field, userCtx, body.Reason, r.RemoteAddr)
+	if err != nil {
+		respondServiceError(w, err, "failed to reveal secret")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"field": body.Field,
+		"value": value,
+	})
+}
+
+func ListSecretAccess(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "id")
+
+	accesses, err := dbHelper.ListSecretAccess(assetID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch secret access audit")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"accesses": accesses,
+	})
+}
+
+// RotateSecretKeys re-wraps stored data keys with the active master key. Run it after adding a
+// new key to MASTER_KEYS and pointing MASTER_KEY_ID at it; the old key can be removed afterwards.
+func RotateSecretKeys(w http.ResponseWriter, r *http.Request) {
+	rewrapped, err := service.RotateSecretKeys()
+	if err != nil {
+		respondServiceError(w, err, "failed to rotate keys")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"rewrapped": rewrapped,
+	})
+}
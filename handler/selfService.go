@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func MyAssets(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	assets, err := service.MyAssets(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch assets")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"assets": assets,
+	})
+}
+
+func MyAssetHistory(w http.ResponseWriter, r *http.Request) {
+	respondUserAssetHistory(w, r, middleware.UserContext(r).UserID)
+}
+
+func AcknowledgeAsset(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := service.AcknowledgeAsset(assetID, userCtx.UserID); err != nil {
+		respondServiceError(w, err, "failed to acknowledge asset")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "receipt acknowledged",
+	})
+}
+
+func ReportAssetIssue(w http.ResponseWriter, r *http.Request) {
+	var body models.ReportIssueRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	issueID, err := service.ReportIssue(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to report issue")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": issueID,
+	})
+}
+
+func MyIssues(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	issues, err := dbHelper.ListAssetIssues(r.URL.Query().Get("status"), userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch issues")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"issues": issues,
+	})
+}
+
+func ListAssetIssues(w http.ResponseWriter, r *http.Request) {
+	// pending issues by default, status=all lists every issue
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "pending"
+	case "all":
+		status = ""
+	}
+
+	issues, err := dbHelper.ListAssetIssues(status, "")
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch issues")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"issues": issues,
+	})
+}
+
+func ResolveAssetIssue(w http.ResponseWriter, r *http.Request) {
+	var body models.ResolveIssueRequest
+	issueID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.ResolveIssue(issueID, userCtx.UserID, body); err != nil {
+		respondServiceError(w, err, "failed to resolve issue")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "issue " + body.Status,
+	})
+}
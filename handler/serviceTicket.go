@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func OpenServiceTicket(w http.ResponseWriter, r *http.Request) {
+	var body models.OpenServiceTicketRequest
+	assetID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	ticketID, err := service.OpenServiceTicket(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to open service ticket")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]string{
+		"id": ticketID,
+	})
+}
+
+func ListServiceTickets(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	status := query.Get("status")
+	assetID := query.Get("assetId")
+
+	tickets, err := dbHelper.ListServiceTickets(status, assetID, false)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch service tickets")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"tickets": tickets,
+	})
+}
+
+func OverdueServiceTickets(w http.ResponseWriter, r *http.Request) {
+	tickets, err := dbHelper.ListServiceTickets("", "", true)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch overdue service tickets")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"tickets": tickets,
+	})
+}
+
+func GetServiceTicket(w http.ResponseWriter, r *http.Request) {
+	ticketID := chi.URLParam(r, "id")
+
+	ticket, err := dbHelper.GetServiceTicket(ticketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "service ticket not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch service ticket")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"ticket": ticket,
+	})
+}
+
+func UpdateServiceTicket(w http.ResponseWriter, r *http.Request) {
+	var body models.UpdateServiceTicketRequest
+	ticketID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.UpdateServiceTicket(ticketID, body); err != nil {
+		respondServiceError(w, err, "failed to update service ticket")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "service ticket updated")
+}
+
+func CloseServiceTicket(w http.ResponseWriter, r *http.Request) {
+	var body models.CloseServiceTicketRequest
+	ticketID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	userCtx := middleware.UserContext(r)
+	if err := service.CloseServiceTicket(ticketID, userCtx.UserID, body); err != nil {
+		respondServiceError(w, err, "failed to close service ticket")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "service ticket closed")
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var body models.RefreshTokenRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	tokens, err := service.RefreshSession(body.RefreshToken, r.UserAgent(), r.RemoteAddr)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			utils.RespondError(w, http.StatusUnauthorized, err, "please login again")
+			return
+		}
+		if errors.Is(err, service.ErrOffboardingLogin) {
+			utils.RespondError(w, http.StatusForbidden, err, "account is being offboarded")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to refresh token")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, tokens)
+}
+
+func ListSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	sessions, err := dbHelper.ListUserSessions(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch sessions")
+		return
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == userCtx.SessionID
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"sessions": sessions,
+	})
+}
+
+func RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+	sessionID := chi.URLParam(r, "id")
+
+	if err := dbHelper.RevokeUserSession(userCtx.UserID, sessionID); err != nil {
+		utils.RespondError(w, http.StatusNotFound, err, "failed to revoke session")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "session revoked",
+	})
+}
+
+func RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	revoked, err := dbHelper.RevokeOtherSessions(userCtx.UserID, userCtx.SessionID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to revoke sessions")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"revoked": revoked,
+	})
+}
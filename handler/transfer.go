@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func InitiateTransfer(w http.ResponseWriter, r *http.Request) {
+	var body models.TransferRequest
+	assetID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	transferID, err := service.InitiateTransfer(assetID, userCtx.UserID, body)
+	if err != nil {
+		respondServiceError(w, err, "failed to start transfer")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id": transferID,
+	})
+}
+
+func MyTransfers(w http.ResponseWriter, r *http.Request) {
+	userCtx := middleware.UserContext(r)
+
+	transfers, err := dbHelper.ListUserTransfers(userCtx.UserID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch transfers")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"transfers": transfers,
+	})
+}
+
+func AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	respondToTransfer(w, r, true)
+}
+
+func DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	respondToTransfer(w, r, false)
+}
+
+func respondToTransfer(w http.ResponseWriter, r *http.Request, accept bool) {
+	transferID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	status, err := service.RespondToTransfer(transferID, userCtx.UserID, accept)
+	if err != nil {
+		respondServiceError(w, err, "failed to answer transfer")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"status": status,
+	})
+}
+
+func CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := service.CancelTransfer(transferID, userCtx.UserID); err != nil {
+		respondServiceError(w, err, "failed to cancel transfer")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "transfer cancelled",
+	})
+}
+
+func ListTransfers(w http.ResponseWriter, r *http.Request) {
+	// transfers waiting for approval by default, status=all lists every transfer
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "pending_approval"
+	case "all":
+		status = ""
+	}
+
+	transfers, err := dbHelper.ListTransfers(status)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch transfers")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"transfers": transfers,
+	})
+}
+
+func ApproveTransfer(w http.ResponseWriter, r *http.Request) {
+	decideTransfer(w, r, true)
+}
+
+func RejectTransfer(w http.ResponseWriter, r *http.Request) {
+	decideTransfer(w, r, false)
+}
+
+func decideTransfer(w http.ResponseWriter, r *http.Request, approve bool) {
+	var body models.DecideTransferRequest
+	transferID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.DecideTransfer(transferID, userCtx.UserID, approve, body.Notes); err != nil {
+		respondServiceError(w, err, "failed to decide transfer")
+		return
+	}
+	message := "transfer rejected"
+	if approve {
+		message = "transfer completed"
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": message,
+	})
+}
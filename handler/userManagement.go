@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/middleware"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/service"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func InviteUser(w http.ResponseWriter, r *http.Request) {
+	var body models.InviteUserRequest
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	userID, err := service.InviteUser(body, userCtx.UserID)
+	if err != nil {
+		if userID != "" {
+			// the account exists, only the mail failed and the invitation can be resent
+			utils.RespondJSON(w, http.StatusCreated, map[string]any{
+				"id":      userID,
+				"message": "user invited, but the invitation email could not be sent",
+			})
+			return
+		}
+		respondServiceError(w, err, "failed to invite user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]any{
+		"id":      userID,
+		"message": "user invited",
+	})
+}
+
+func ResendInvite(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+
+	if err := service.ResendInvite(userID); err != nil {
+		respondServiceError(w, err, "failed to resend invitation")
+		return
+	}
+	utils.RespondJSON(w, http.StatusAccepted, map[string]any{
+		"message": "invitation sent",
+	})
+}
+
+func AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	var body models.AcceptInviteRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	tokens, err := service.AcceptInvite(body.Token, body.Password, r.UserAgent(), r.RemoteAddr)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			utils.RespondError(w, http.StatusBadRequest, err, "invitation is invalid or expired")
+			return
+		}
+		respondServiceError(w, err, "failed to accept invitation")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, tokens)
+}
+
+func ChangeUserRole(w http.ResponseWriter, r *http.Request) {
+	var body models.ChangeRoleRequest
+	userID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	if err := service.ChangeUserRole(userCtx.UserID, userID, body.Role); err != nil {
+		respondServiceError(w, err, "failed to change role")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "role updated",
+	})
+}
+
+func ArchiveUser(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	userCtx := middleware.UserContext(r)
+
+	if err := service.ArchiveUser(userCtx.UserID, userID); err != nil {
+		respondServiceError(w, err, "failed to archive user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "user archived",
+	})
+}
+
+func UnarchiveUser(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+
+	if err := service.UnarchiveUser(userID); err != nil {
+		respondServiceError(w, err, "failed to unarchive user")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"message": "user unarchived",
+	})
+}
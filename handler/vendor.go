@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+func CreateVendor(w http.ResponseWriter, r *http.Request) {
+	var body models.VendorRequest
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	exist, err := dbHelper.IsVendorExist(body.Name, "")
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check vendor existence")
+		return
+	}
+	if exist {
+		utils.RespondError(w, http.StatusConflict, nil, "vendor exist")
+		return
+	}
+
+	vendorID, err := dbHelper.CreateVendor(body)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create vendor")
+		return
+	}
+	utils.RespondJSON(w, http.StatusCreated, map[string]string{
+		"id": vendorID,
+	})
+}
+
+func ListVendors(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	vendors, err := dbHelper.ListVendors(name)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch vendors")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"vendors": vendors,
+	})
+}
+
+func GetVendor(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	vendor, err := dbHelper.GetVendor(vendorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusNotFound, err, "vendor not found")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to fetch vendor")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"vendor": vendor,
+	})
+}
+
+func UpdateVendor(w http.ResponseWriter, r *http.Request) {
+	var body models.VendorRequest
+	vendorID := chi.URLParam(r, "id")
+
+	if err := utils.ParseBody(r.Body, &body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse body")
+		return
+	}
+	validateErr := validate.Struct(&body)
+	if validateErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validateErr, "fail to validate body")
+		return
+	}
+
+	exist, err := dbHelper.IsVendorExist(body.Name, vendorID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to check vendor existence")
+		return
+	}
+	if exist {
+		utils.RespondError(w, http.StatusConflict, nil, "vendor exist")
+		return
+	}
+
+	if err := dbHelper.UpdateVendor(vendorID, body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to update vendor")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "vendor updated")
+}
+
+func DeleteVendor(w http.ResponseWriter, r *http.Request) {
+	vendorID := chi.URLParam(r, "id")
+
+	if err := dbHelper.DeleteVendor(vendorID); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to delete vendor")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, "vendor deleted")
+}
+
+func VendorReport(w http.ResponseWriter, r *http.Request) {
+	report, err := dbHelper.VendorReport()
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to build vendor report")
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]any{
+		"vendors": report,
+	})
+}
@@ -0,0 +1,63 @@
+// Package jobs runs the periodic background work of the server, such as converting asset
+// reservations on their start date.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/service"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Default returns the jobs the server runs.
+func Default() []Job {
+	return []Job{
+		{Name: "reservations", Interval: 15 * time.Minute, Run: service.ProcessReservations},
+		{Name: "rotation-reminders", Interval: time.Hour, Run: service.SendRotationReminders},
+	}
+}
+
+// Start runs every job once right away and then on its interval, until the context is done.
+// A failing or panicking run is logged and retried on the next tick. Every server instance runs
+// the jobs, so each run holds a Postgres advisory lock and is skipped while another instance
+// runs the same job. Start needs a connected database.
+func Start(ctx context.Context, jobs []Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		if err := runOnce(ctx, job, time.Now()); err != nil {
+			fmt.Printf("job %s failed: %v\n", job.Name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, job Job, now time.Time) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	_, err = database.WithAdvisoryLock(ctx, "job:"+job.Name, func() error {
+		return job.Run(now)
+	})
+	return err
+}
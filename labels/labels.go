@@ -0,0 +1,199 @@
+// Package labels renders the printable labels stuck on assets: a QR code of the asset tag, a
+// Code128 barcode of the serial number and both in plain text.
+package labels
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/jung-kurt/gofpdf"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+type Label struct {
+	AssetTag     string
+	SerialNumber string
+	Brand        string
+	Model        string
+}
+
+// QRCode encodes the asset tag, which is what the scan lookup expects first.
+func QRCode(label Label, size int) (image.Image, error) {
+	code, err := qr.Encode(label.AssetTag, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode qr code: %w", err)
+	}
+	return barcode.Scale(code, max(size, code.Bounds().Dx()), max(size, code.Bounds().Dy()))
+}
+
+// Code128 encodes the serial number, with bars moduleWidth pixels wide.
+func Code128(label Label, moduleWidth, height int) (image.Image, error) {
+	code, err := code128.Encode(label.SerialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode serial number %q as code128: %w", label.SerialNumber, err)
+	}
+	return barcode.Scale(code, code.Bounds().Dx()*moduleWidth, height)
+}
+
+// encodePNG writes an 8-bit grayscale PNG; barcodes come as 16-bit gray, which PDF readers
+// and gofpdf do not all support.
+func encodePNG(img image.Image) ([]byte, error) {
+	gray := image.NewGray(img.Bounds())
+	draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, gray); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func QRCodePNG(label Label) ([]byte, error) {
+	img, err := QRCode(label, 300)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(img)
+}
+
+func Code128PNG(label Label) ([]byte, error) {
+	img, err := Code128(label, 3, 120)
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(img)
+}
+
+// padding leaves around the QR code the quiet zone of four modules scanners need.
+const (
+	padding   = 40
+	qrSize    = 240
+	textScale = 2
+)
+
+// drawText writes lines in the basic 7x13 font, scaled up so that they stay legible on print,
+// and returns the height used.
+func drawText(dst draw.Image, x, y int, lines []string) int {
+	face := basicfont.Face7x13
+	lineHeight := face.Metrics().Height.Ceil()
+	width := 0
+	for _, line := range lines {
+		width = max(width, font.MeasureString(face, line).Ceil())
+	}
+	text := image.NewRGBA(image.Rect(0, 0, width, lineHeight*len(lines)))
+	draw.Draw(text, text.Bounds(), image.White, image.Point{}, draw.Src)
+	drawer := font.Drawer{Dst: text, Src: image.Black, Face: face}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(0, lineHeight*i+face.Metrics().Ascent.Ceil())
+		drawer.DrawString(line)
+	}
+	target := image.Rect(x, y, x+text.Bounds().Dx()*textScale, y+text.Bounds().Dy()*textScale)
+	xdraw.NearestNeighbor.Scale(dst, target, text, text.Bounds(), draw.Src, nil)
+	return target.Dy()
+}
+
+// LabelPNG lays a full label out: the QR code on the left, the text and the barcode on the right.
+func LabelPNG(label Label) ([]byte, error) {
+	qrCode, err := QRCode(label, qrSize)
+	if err != nil {
+		return nil, err
+	}
+	barcodeImg, err := Code128(label, 2, 100)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{label.AssetTag, "S/N " + label.SerialNumber, label.Brand + " " + label.Model}
+	textWidth := 0
+	for _, line := range lines {
+		textWidth = max(textWidth, font.MeasureString(basicfont.Face7x13, line).Ceil()*textScale)
+	}
+
+	right := padding + qrCode.Bounds().Dx() + padding
+	width := right + max(textWidth, barcodeImg.Bounds().Dx()) + padding
+	height := padding + max(qrCode.Bounds().Dy(), 3*13*textScale+padding+barcodeImg.Bounds().Dy()) + padding
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+
+	draw.Draw(img, qrCode.Bounds().Add(image.Pt(padding, padding)), qrCode, image.Point{}, draw.Src)
+	textHeight := drawText(img, right, padding, lines)
+	draw.Draw(img, barcodeImg.Bounds().Add(image.Pt(right, padding+textHeight+padding)), barcodeImg, image.Point{}, draw.Src)
+	return encodePNG(img)
+}
+
+// Avery L7160 sheet: 3 columns of 7 labels of 63.5 x 38.1 mm on A4.
+const (
+	sheetColumns = 3
+	sheetRows    = 7
+	labelWidth   = 63.5
+	labelHeight  = 38.1
+	sheetLeft    = 7.2
+	sheetTop     = 15.15
+	columnGap    = 2.5
+)
+
+// fit shortens a string until it fits in width at the current font.
+func fit(pdf *gofpdf.Fpdf, s string, width float64) string {
+	for len(s) > 0 && pdf.GetStringWidth(s) > width {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// SheetPDF lays the labels out on as many A4 sheets of stickers as needed.
+func SheetPDF(labels []Label) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+
+	for i, label := range labels {
+		slot := i % (sheetColumns * sheetRows)
+		if slot == 0 {
+			pdf.AddPage()
+		}
+		x := sheetLeft + float64(slot%sheetColumns)*(labelWidth+columnGap)
+		y := sheetTop + float64(slot/sheetColumns)*labelHeight
+
+		qrPNG, err := QRCodePNG(label)
+		if err != nil {
+			return nil, err
+		}
+		barcodePNG, err := Code128PNG(label)
+		if err != nil {
+			return nil, err
+		}
+		qrName := fmt.Sprintf("qr-%d", i)
+		barcodeName := fmt.Sprintf("code128-%d", i)
+		pdf.RegisterImageOptionsReader(qrName, options, bytes.NewReader(qrPNG))
+		pdf.RegisterImageOptionsReader(barcodeName, options, bytes.NewReader(barcodePNG))
+
+		pdf.ImageOptions(qrName, x+2, y+3, 26, 26, false, options, 0, "")
+		textX := x + 30
+		textWidth := labelWidth - 32
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.Text(textX, y+7, fit(pdf, translate(label.AssetTag), textWidth))
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.Text(textX, y+11, fit(pdf, translate("S/N "+label.SerialNumber), textWidth))
+		pdf.Text(textX, y+14.5, fit(pdf, translate(label.Brand+" "+label.Model), textWidth))
+		pdf.ImageOptions(barcodeName, textX, y+17, textWidth, 12, false, options, 0, "")
+	}
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,135 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+// SMTPMailer delivers mail through an SMTP relay, authenticating when a username is set.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, []string{message.To}, format(m.From, message))
+}
+
+// LogMailer writes every message to a file in Dir, or to stdout when Dir is empty. It is meant
+// for development only, as the messages hold bearer links.
+type LogMailer struct {
+	Dir  string
+	From string
+}
+
+func (m LogMailer) Send(message Message) error {
+	raw := format(m.From, message)
+	if m.Dir == "" {
+		fmt.Printf("mail to %s:\n%s\n", message.To, raw)
+		return nil
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(message.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), raw, 0o600)
+}
+
+func format(from string, message Message) []byte {
+	return []byte("From: " + from + "\r\n" +
+		"To: " + message.To + "\r\n" +
+		"Subject: " + message.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + message.Body + "\r\n")
+}
+
+var (
+	defaultMailer Mailer
+	defaultOnce   sync.Once
+)
+
+var ErrNotConfigured = errors.New("MAILER must be set to smtp, with SMTP_HOST, or to log")
+
+// FromEnv builds the mailer configured by the environment: MAILER=smtp with SMTP_HOST,
+// SMTP_PORT, SMTP_USERNAME and SMTP_PASSWORD, or MAILER=log for a LogMailer writing to
+// MAIL_DIR. MAIL_FROM sets the sender for both. Mail is never logged unless asked for, since
+// the messages carry reset, verification and invitation links.
+func FromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "storex@localhost"
+	}
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		if os.Getenv("SMTP_HOST") == "" {
+			return nil, ErrNotConfigured
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	case "log":
+		return LogMailer{Dir: os.Getenv("MAIL_DIR"), From: from}, nil
+	}
+	return nil, ErrNotConfigured
+}
+
+// unconfiguredMailer fails every message with the reason the environment gave no mailer.
+type unconfiguredMailer struct {
+	err error
+}
+
+func (m unconfiguredMailer) Send(Message) error {
+	return m.err
+}
+
+// Default returns the mailer set with SetDefault, or else the one FromEnv configures. Without
+// a valid configuration every message fails.
+func Default() Mailer {
+	defaultOnce.Do(func() {
+		m, err := FromEnv()
+		if err != nil {
+			m = unconfiguredMailer{err: err}
+		}
+		defaultMailer = m
+	})
+	return defaultMailer
+}
+
+// SetDefault replaces the mailer returned by Default, for a transport configured outside this package.
+func SetDefault(m Mailer) {
+	defaultOnce.Do(func() {})
+	defaultMailer = m
+}
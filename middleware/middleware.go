@@ -41,7 +41,7 @@ func Auth(next http.Handler) http.Handler {
 			return
 		}
 		sessionID := claimValues["sessionId"].(string)
-		userID, err := dbHelper.VaidateSession(sessionID)
+		userID, mfaVerified, err := dbHelper.VaidateSession(sessionID)
 		//fmt.Println(userID)
 		if err != nil {
 			//http.Error(w, "invalid user", http.StatusUnauthorized)
@@ -55,9 +55,10 @@ func Auth(next http.Handler) http.Handler {
 		//}
 
 		user := &models.UserCtx{
-			UserID:    userID,
-			SessionID: sessionID,
-			Role:      claimValues["role"].(string),
+			UserID:      userID,
+			SessionID:   sessionID,
+			Role:        claimValues["role"].(string),
+			MFAVerified: mfaVerified,
 		}
 		ctx := context.WithValue(r.Context(), userContextKey, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -68,19 +69,3 @@ func UserContext(r *http.Request) *models.UserCtx {
 	user, _ := r.Context().Value(userContextKey).(*models.UserCtx)
 	return user
 }
-func RoleMiddleware(Roles ...string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userCtx := UserContext(r)
-			UserRole := userCtx.Role
-
-			for _, role := range Roles {
-				if UserRole == role {
-					next.ServeHTTP(w, r)
-					return
-				}
-			}
-			utils.RespondError(w, http.StatusForbidden, nil, "not-authorised")
-		})
-	}
-}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+// permissionCacheTTL bounds how long a session keeps permissions that were since removed from its role.
+const permissionCacheTTL = time.Minute
+
+type cachedPermissions struct {
+	permissions models.SessionPermissions
+	expiresAt   time.Time
+}
+
+// permissionCache holds the permissions of each session, so checking a route does not query
+// the role tables on every request.
+var permissionCache = struct {
+	sync.Mutex
+	bySession map[string]cachedPermissions
+}{bySession: make(map[string]cachedPermissions)}
+
+// InvalidatePermissionCache drops every cached permission set, call it after editing roles.
+func InvalidatePermissionCache() {
+	permissionCache.Lock()
+	defer permissionCache.Unlock()
+	permissionCache.bySession = make(map[string]cachedPermissions)
+}
+
+func sessionPermissions(sessionID string) (models.SessionPermissions, error) {
+	permissionCache.Lock()
+	defer permissionCache.Unlock()
+
+	now := time.Now()
+	if cached, ok := permissionCache.bySession[sessionID]; ok && now.Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+	permissions, err := dbHelper.GetSessionPermissions(sessionID)
+	if err != nil {
+		return permissions, err
+	}
+	for id, cached := range permissionCache.bySession {
+		if now.After(cached.expiresAt) {
+			delete(permissionCache.bySession, id)
+		}
+	}
+	permissionCache.bySession[sessionID] = cachedPermissions{permissions: permissions, expiresAt: now.Add(permissionCacheTTL)}
+	return permissions, nil
+}
+
+// sessionRestriction tells why a session grants no permission at all, or returns "" when it
+// may use the permissions of its role. Sessions of roles that require MFA grant nothing until
+// MFA is passed, and users grant nothing until their email address is verified.
+func sessionRestriction(permissions models.SessionPermissions, userCtx *models.UserCtx) string {
+	if permissions.MFARequired && !userCtx.MFAVerified {
+		return "two-factor authentication required for this role"
+	}
+	if !permissions.EmailVerified {
+		return "verify your email address first"
+	}
+	return ""
+}
+
+// SessionRestriction tells why the session of the request may not act for its role, or returns
+// "" when it may. Checks that depend on the role rather than a permission, such as deciding an
+// approval step, use it to get the same MFA and verification rules as RequirePermission.
+func SessionRestriction(r *http.Request) (string, error) {
+	userCtx := UserContext(r)
+	permissions, err := sessionPermissions(userCtx.SessionID)
+	if err != nil {
+		return "", err
+	}
+	return sessionRestriction(permissions, userCtx), nil
+}
+
+// HasPermission reports whether the session of the request grants the permission.
+func HasPermission(r *http.Request, permission string) (bool, error) {
+	userCtx := UserContext(r)
+	permissions, err := sessionPermissions(userCtx.SessionID)
+	if err != nil {
+		return false, err
+	}
+	if sessionRestriction(permissions, userCtx) != "" {
+		return false, nil
+	}
+	return slices.Contains(permissions.Permissions, permission), nil
+}
+
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userCtx := UserContext(r)
+			permissions, err := sessionPermissions(userCtx.SessionID)
+			if err != nil {
+				utils.RespondError(w, http.StatusInternalServerError, err, "failed to load permissions")
+				return
+			}
+			if restriction := sessionRestriction(permissions, userCtx); restriction != "" {
+				utils.RespondError(w, http.StatusForbidden, nil, restriction)
+				return
+			}
+			if !slices.Contains(permissions.Permissions, permission) {
+				utils.RespondError(w, http.StatusForbidden, nil, "not-authorised")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
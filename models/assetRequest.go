@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+)
+
+type AssetRequest struct {
+	ID               string     `json:"id" db:"id"`
+	RequestedBy      string     `json:"requestedBy" db:"requested_by"`
+	RequestedByName  string     `json:"requestedByName" db:"requested_by_name"`
+	AssetType        string     `json:"assetType" db:"asset_type"`
+	Justification    string     `json:"justification" db:"justification"`
+	NeededBy         *time.Time `json:"neededBy" db:"needed_by"`
+	Status           string     `json:"status" db:"status"`
+	FulfilledAssetID *string    `json:"fulfilledAssetId" db:"fulfilled_asset_id"`
+	CreatedAt        time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt        *time.Time `json:"updatedAt" db:"updated_at"`
+	ClosedAt         *time.Time `json:"closedAt" db:"closed_at"`
+}
+
+// AssetRequestApproval is one step of the approval chain of a request. The approver is a user
+// for a manager step and any user with the role for a role step.
+type AssetRequestApproval struct {
+	ID           string     `json:"id" db:"id"`
+	RequestID    string     `json:"requestId" db:"request_id"`
+	Position     int        `json:"position" db:"position"`
+	ApproverKind string     `json:"approverKind" db:"approver_kind"`
+	ApproverRole *string    `json:"approverRole" db:"approver_role"`
+	ApproverID   *string    `json:"approverId" db:"approver_id"`
+	Decision     *string    `json:"decision" db:"decision"`
+	DecidedBy    *string    `json:"decidedBy" db:"decided_by"`
+	DecidedAt    *time.Time `json:"decidedAt" db:"decided_at"`
+	Comment      *string    `json:"comment" db:"comment"`
+}
+type AssetRequestEvent struct {
+	ID         string    `json:"id" db:"id"`
+	RequestID  string    `json:"requestId" db:"request_id"`
+	ActorID    *string   `json:"actorId" db:"actor_id"`
+	ActorName  *string   `json:"actorName" db:"actor_name"`
+	Event      string    `json:"event" db:"event"`
+	FromStatus *string   `json:"fromStatus" db:"from_status"`
+	ToStatus   string    `json:"toStatus" db:"to_status"`
+	Notes      *string   `json:"notes" db:"notes"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+type AssetRequestDetail struct {
+	AssetRequest
+	Approvals []AssetRequestApproval `json:"approvals"`
+	Events    []AssetRequestEvent    `json:"events"`
+}
+type CreateAssetRequestRequest struct {
+	AssetType     string     `json:"assetType" validate:"required"`
+	Justification string     `json:"justification" validate:"required,max=2000"`
+	NeededBy      *time.Time `json:"neededBy"`
+}
+type DecideAssetRequestRequest struct {
+	Comment string `json:"comment" validate:"max=1000"`
+}
+type FulfilAssetRequestRequest struct {
+	AssetID string `json:"assetId" validate:"required,uuid"`
+}
+type ApprovalStep struct {
+	Position     int     `json:"position" db:"position"`
+	ApproverKind string  `json:"approverKind" db:"approver_kind"`
+	ApproverRole *string `json:"approverRole" db:"approver_role"`
+}
+type ApprovalStepRequest struct {
+	ApproverKind string `json:"approverKind" validate:"required,oneof=manager role"`
+	ApproverRole string `json:"approverRole" validate:"required_if=ApproverKind role,excluded_if=ApproverKind manager"`
+}
+type ApprovalStepsRequest struct {
+	Steps []ApprovalStepRequest `json:"steps" validate:"max=10,dive"`
+}
+type ChangeManagerRequest struct {
+	// ManagerID is left empty to remove the manager.
+	ManagerID string `json:"managerId" validate:"omitempty,uuid"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AssetType struct {
+	Name       string          `json:"name" db:"name"`
+	Label      string          `json:"label" db:"label"`
+	SpecSchema json.RawMessage `json:"specSchema" db:"spec_schema"`
+	// TransferRequiresApproval makes transfers between employees wait for an asset manager.
+	TransferRequiresApproval bool      `json:"transferRequiresApproval" db:"transfer_requires_approval"`
+	CreatedAt                time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt                time.Time `json:"updatedAt" db:"updated_at"`
+}
+type AssetTypeRequest struct {
+	Name                     string          `json:"name" validate:"required,max=50"`
+	Label                    string          `json:"label" validate:"required,max=100"`
+	SpecSchema               json.RawMessage `json:"specSchema" validate:"required"`
+	TransferRequiresApproval bool            `json:"transferRequiresApproval"`
+}
+type UpdateAssetTypeRequest struct {
+	Label                    string          `json:"label" validate:"required,max=100"`
+	SpecSchema               json.RawMessage `json:"specSchema" validate:"required"`
+	TransferRequiresApproval bool            `json:"transferRequiresApproval"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditCampaign struct {
+	ID          string     `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	Scope       string     `json:"scope" db:"scope"`
+	ScopeValue  *string    `json:"scopeValue" db:"scope_value"`
+	Notes       *string    `json:"notes" db:"notes"`
+	Status      string     `json:"status" db:"status"`
+	OpenedBy    string     `json:"openedBy" db:"opened_by"`
+	OpenedAt    time.Time  `json:"openedAt" db:"opened_at"`
+	ClosedBy    *string    `json:"closedBy" db:"closed_by"`
+	ClosedAt    *time.Time `json:"closedAt" db:"closed_at"`
+	FlaggedLost bool       `json:"flaggedLost" db:"flagged_lost"`
+	Expected    int        `json:"expected" db:"expected"`
+	Found       int        `json:"found" db:"found"`
+	Missing     int        `json:"missing" db:"missing"`
+	Mismatched  int        `json:"mismatched" db:"mismatched"`
+	Pending     int        `json:"pending" db:"pending"`
+	Unexpected  int        `json:"unexpected" db:"unexpected"`
+}
+type AuditItem struct {
+	AssetID            string     `json:"assetId" db:"asset_id"`
+	SerialNumber       string     `json:"serialNumber" db:"serial_number"`
+	AssetType          string     `json:"type" db:"type"`
+	Brand              string     `json:"brand" db:"brand"`
+	Model              string     `json:"model" db:"model"`
+	Expected           bool       `json:"expected" db:"expected"`
+	ExpectedStatus     string     `json:"expectedStatus" db:"expected_status"`
+	ExpectedHolder     *string    `json:"expectedHolder" db:"expected_holder"`
+	ExpectedHolderName *string    `json:"expectedHolderName" db:"expected_holder_name"`
+	Result             string     `json:"result" db:"result"`
+	Notes              *string    `json:"notes" db:"notes"`
+	CheckedBy          *string    `json:"checkedBy" db:"checked_by"`
+	CheckedAt          *time.Time `json:"checkedAt" db:"checked_at"`
+	FlaggedLost        bool       `json:"flaggedLost" db:"flagged_lost"`
+}
+type OpenAuditRequest struct {
+	Name  string `json:"name" validate:"required,max=200"`
+	Scope string `json:"scope" validate:"required,oneof=all type owner location"`
+	// ScopeValue is the asset type, owner or location audited, unused for the all scope.
+	ScopeValue string `json:"scopeValue" validate:"required_unless=Scope all,excluded_if=Scope all"`
+	Notes      string `json:"notes" validate:"max=1000"`
+}
+type AuditResultRequest struct {
+	SerialNumber string `json:"serialNumber" validate:"required"`
+	Result       string `json:"result" validate:"required,oneof=found missing mismatched"`
+	// Notes say what does not match for a mismatched asset.
+	Notes string `json:"notes" validate:"required_if=Result mismatched,max=1000"`
+}
+type RecordAuditResultsRequest struct {
+	Results []AuditResultRequest `json:"results" validate:"required,min=1,max=500,dive"`
+}
+type CloseAuditRequest struct {
+	// FlagMissingAsLost reports every asset marked missing as lost.
+	FlagMissingAsLost bool `json:"flagMissingAsLost"`
+}
+
+// AuditReport is the discrepancy report of a campaign: every asset that was not simply found
+// where expected, that is missing, mismatched, never checked or found outside the snapshot.
+type AuditReport struct {
+	Campaign      AuditCampaign `json:"campaign"`
+	Discrepancies []AuditItem   `json:"discrepancies"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CustomFieldDefinition struct {
+	ID            string         `json:"id" db:"id"`
+	Entity        string         `json:"entity" db:"entity"`
+	Key           string         `json:"key" db:"key"`
+	Label         string         `json:"label" db:"label"`
+	FieldType     string         `json:"fieldType" db:"field_type"`
+	Required      bool           `json:"required" db:"required"`
+	AllowedValues pq.StringArray `json:"allowedValues" db:"allowed_values"`
+	CreatedAt     time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt     *time.Time     `json:"updatedAt" db:"updated_at"`
+}
+type CustomFieldDefinitionRequest struct {
+	Entity        string   `json:"entity" validate:"required,oneof=asset user"`
+	Key           string   `json:"key" validate:"required,max=50"`
+	Label         string   `json:"label" validate:"required,max=100"`
+	FieldType     string   `json:"fieldType" validate:"required,oneof=text number boolean date select"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowedValues" validate:"required_if=FieldType select,dive,required"`
+}
+type UpdateCustomFieldDefinitionRequest struct {
+	Label         string   `json:"label" validate:"required,max=100"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowedValues" validate:"dive,required"`
+}
+type UserCustomFieldsRequest struct {
+	CustomFields json.RawMessage `json:"customFields"`
+}
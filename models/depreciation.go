@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+type DepreciationPolicy struct {
+	AssetType        string     `json:"assetType" db:"asset_type"`
+	Method           string     `json:"method" db:"method"`
+	UsefulLifeMonths int        `json:"usefulLifeMonths" db:"useful_life_months"`
+	SalvagePercent   float64    `json:"salvagePercent" db:"salvage_percent"`
+	DecliningRate    *float64   `json:"decliningRate" db:"declining_rate"`
+	UpdatedAt        *time.Time `json:"updatedAt" db:"updated_at"`
+}
+type DepreciationPolicyRequest struct {
+	Method           string   `json:"method" validate:"required,oneof=straight_line declining_balance"`
+	UsefulLifeMonths int      `json:"usefulLifeMonths" validate:"required,gt=0"`
+	SalvagePercent   float64  `json:"salvagePercent" validate:"gte=0,lte=100"`
+	DecliningRate    *float64 `json:"decliningRate" validate:"omitempty,gt=0,lt=100"`
+}
+
+// ValuedAsset is an asset together with its purchase details and the depreciation policy
+// of its type; the policy fields are nil when its type has none.
+type ValuedAsset struct {
+	ID               string     `db:"id"`
+	SerialNumber     string     `db:"serial_number"`
+	AssetType        string     `db:"type"`
+	PurchaseDate     *time.Time `db:"purchase_date"`
+	PurchasePrice    *float64   `db:"purchase_price"`
+	Currency         *string    `db:"currency"`
+	Method           *string    `db:"method"`
+	UsefulLifeMonths *int       `db:"useful_life_months"`
+	SalvagePercent   *float64   `db:"salvage_percent"`
+	DecliningRate    *float64   `db:"declining_rate"`
+}
+type BookValue struct {
+	AssetID                 string    `json:"assetID"`
+	SerialNumber            string    `json:"serialNumber"`
+	AssetType               string    `json:"type"`
+	PurchaseDate            time.Time `json:"purchaseDate"`
+	PurchasePrice           float64   `json:"purchasePrice"`
+	Currency                string    `json:"currency"`
+	Method                  string    `json:"method"`
+	UsefulLifeMonths        int       `json:"usefulLifeMonths"`
+	AccumulatedDepreciation float64   `json:"accumulatedDepreciation"`
+	BookValue               float64   `json:"bookValue"`
+	AsOf                    time.Time `json:"asOf"`
+}
+type DepreciationTotal struct {
+	Currency                string  `json:"currency"`
+	Assets                  int     `json:"assets"`
+	Cost                    float64 `json:"cost"`
+	AccumulatedDepreciation float64 `json:"accumulatedDepreciation"`
+	BookValue               float64 `json:"bookValue"`
+}
+type DepreciationReport struct {
+	AsOf     time.Time           `json:"asOf"`
+	Totals   []DepreciationTotal `json:"totals"`
+	Assets   []BookValue         `json:"assets"`
+	Unvalued []string            `json:"unvaluedAssets"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+type AssetDisposal struct {
+	ID                    string     `json:"id" db:"id"`
+	AssetID               string     `json:"assetId" db:"asset_id"`
+	SerialNumber          string     `json:"serialNumber" db:"serial_number"`
+	AssetType             string     `json:"assetType" db:"type"`
+	Method                string     `json:"method" db:"method"`
+	Reason                string     `json:"reason" db:"reason"`
+	RequestedBy           string     `json:"requestedBy" db:"requested_by"`
+	RequestedByName       string     `json:"requestedByName" db:"requested_by_name"`
+	Status                string     `json:"status" db:"status"`
+	CreatedAt             time.Time  `json:"createdAt" db:"created_at"`
+	DecidedBy             *string    `json:"decidedBy" db:"decided_by"`
+	DecidedAt             *time.Time `json:"decidedAt" db:"decided_at"`
+	DecisionNotes         *string    `json:"decisionNotes" db:"decision_notes"`
+	DisposedOn            *time.Time `json:"disposedOn" db:"disposed_on"`
+	Recipient             *string    `json:"recipient" db:"recipient"`
+	Proceeds              *float64   `json:"proceeds" db:"proceeds"`
+	Currency              *string    `json:"currency" db:"currency"`
+	DataWiped             bool       `json:"dataWiped" db:"data_wiped"`
+	WipeMethod            *string    `json:"wipeMethod" db:"wipe_method"`
+	BookValue             *float64   `json:"bookValue" db:"book_value"`
+	GainLoss              *float64   `json:"gainLoss" db:"gain_loss"`
+	CompletedBy           *string    `json:"completedBy" db:"completed_by"`
+	CompletedAt           *time.Time `json:"completedAt" db:"completed_at"`
+	CertificateName       *string    `json:"certificateName" db:"certificate_name"`
+	CertificateUploadedAt *time.Time `json:"certificateUploadedAt" db:"certificate_uploaded_at"`
+}
+type DisposalCertificate struct {
+	Name        string `db:"certificate_name"`
+	ContentType string `db:"certificate_content_type"`
+	Content     []byte `db:"certificate"`
+}
+type RequestDisposalRequest struct {
+	Method string `json:"method" validate:"required,oneof=sold donated recycled returned_to_client"`
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+type DecideDisposalRequest struct {
+	Notes string `json:"notes" validate:"max=1000"`
+}
+type CompleteDisposalRequest struct {
+	DisposedOn time.Time `json:"disposedOn" validate:"required"`
+	// Recipient is the buyer, charity, recycler or client the asset went to.
+	Recipient  string   `json:"recipient" validate:"required,max=200"`
+	Proceeds   *float64 `json:"proceeds" validate:"omitempty,gte=0"`
+	Currency   string   `json:"currency" validate:"omitempty,len=3"`
+	DataWiped  bool     `json:"dataWiped"`
+	WipeMethod string   `json:"wipeMethod" validate:"max=200"`
+}
+type DisposalTotal struct {
+	Currency  string  `json:"currency"`
+	Assets    int     `json:"assets"`
+	Cost      float64 `json:"cost"`
+	BookValue float64 `json:"bookValue"`
+	Proceeds  float64 `json:"proceeds"`
+	GainLoss  float64 `json:"gainLoss"`
+}
+type DisposedAsset struct {
+	DisposalID    string    `json:"disposalId" db:"id"`
+	AssetID       string    `json:"assetId" db:"asset_id"`
+	SerialNumber  string    `json:"serialNumber" db:"serial_number"`
+	AssetType     string    `json:"type" db:"type"`
+	Method        string    `json:"method" db:"method"`
+	DisposedOn    time.Time `json:"disposedOn" db:"disposed_on"`
+	Recipient     string    `json:"recipient" db:"recipient"`
+	PurchasePrice *float64  `json:"purchasePrice" db:"purchase_price"`
+	BookValue     *float64  `json:"bookValue" db:"book_value"`
+	Proceeds      *float64  `json:"proceeds" db:"proceeds"`
+	Currency      *string   `json:"currency" db:"currency"`
+	GainLoss      *float64  `json:"gainLoss" db:"gain_loss"`
+	Certificate   bool      `json:"hasCertificate" db:"has_certificate"`
+}
+
+// DisposalReport lists the disposals completed in a period with the write-off they caused,
+// totalled per currency. Disposals whose book value is unknown are left out of the totals.
+type DisposalReport struct {
+	From      *time.Time      `json:"from"`
+	To        *time.Time      `json:"to"`
+	Totals    []DisposalTotal `json:"totals"`
+	Disposals []DisposedAsset `json:"disposals"`
+}
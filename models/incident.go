@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+type AssetIncident struct {
+	ID                 string     `json:"id" db:"id"`
+	AssetID            string     `json:"assetId" db:"asset_id"`
+	SerialNumber       string     `json:"serialNumber" db:"serial_number"`
+	Kind               string     `json:"kind" db:"kind"`
+	ReportedBy         string     `json:"reportedBy" db:"reported_by"`
+	ReportedByName     string     `json:"reportedByName" db:"reported_by_name"`
+	OccurredOn         time.Time  `json:"occurredOn" db:"occurred_on"`
+	Location           *string    `json:"location" db:"location"`
+	PoliceReportNumber *string    `json:"policeReportNumber" db:"police_report_number"`
+	Remarks            *string    `json:"remarks" db:"remarks"`
+	Status             string     `json:"status" db:"status"`
+	RecoveredBy        *string    `json:"recoveredBy" db:"recovered_by"`
+	RecoveredAt        *time.Time `json:"recoveredAt" db:"recovered_at"`
+	RecoveryCondition  *string    `json:"recoveryCondition" db:"recovery_condition"`
+	RecoveryNotes      *string    `json:"recoveryNotes" db:"recovery_notes"`
+	CreatedAt          time.Time  `json:"createdAt" db:"created_at"`
+}
+type ReportIncidentRequest struct {
+	Kind               string    `json:"kind" validate:"required,oneof=lost stolen"`
+	OccurredOn         time.Time `json:"occurredOn" validate:"required"`
+	Location           string    `json:"location" validate:"max=500"`
+	PoliceReportNumber string    `json:"policeReportNumber" validate:"max=100"`
+	Remarks            string    `json:"remarks" validate:"max=2000"`
+}
+type RecoverAssetRequest struct {
+	Condition string `json:"condition" validate:"required,oneof=good fair needs_repair damaged"`
+	Notes     string `json:"notes" validate:"max=1000"`
+	// LocationID is where the recovered asset is kept from now on.
+	LocationID string `json:"locationId" validate:"omitempty,uuid"`
+}
+type RotationReminder struct {
+	ID             string     `json:"id" db:"id"`
+	AssetID        string     `json:"assetId" db:"asset_id"`
+	SerialNumber   string     `json:"serialNumber" db:"serial_number"`
+	IncidentID     string     `json:"incidentId" db:"incident_id"`
+	IncidentKind   string     `json:"incidentKind" db:"incident_kind"`
+	Field          string     `json:"field" db:"field"`
+	Status         string     `json:"status" db:"status"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	LastRemindedAt *time.Time `json:"lastRemindedAt" db:"last_reminded_at"`
+	CompletedAt    *time.Time `json:"completedAt" db:"completed_at"`
+	CompletedBy    *string    `json:"completedBy" db:"completed_by"`
+}
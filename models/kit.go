@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type KitTemplate struct {
+	ID             string  `json:"id" db:"id"`
+	Name           string  `json:"name" db:"name"`
+	Role           *string `json:"role" db:"role"`
+	EmploymentType *string `json:"employmentType" db:"employment_type"`
+	// Items is the list of KitItem of the template.
+	Items     json.RawMessage `json:"items" db:"items"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt *time.Time      `json:"updatedAt" db:"updated_at"`
+}
+type KitItem struct {
+	AssetType string `json:"assetType" db:"asset_type" validate:"required"`
+	Quantity  int    `json:"quantity" db:"quantity" validate:"required,min=1,max=20"`
+}
+type KitTemplateRequest struct {
+	Name           string    `json:"name" validate:"required,max=100"`
+	Role           string    `json:"role" validate:"max=50"`
+	EmploymentType string    `json:"employmentType" validate:"omitempty,oneof=full-time intern freelancer"`
+	Items          []KitItem `json:"items" validate:"required,min=1,dive"`
+}
+type AssignKitRequest struct {
+	// TemplateID is left empty to use the template that best matches the role and employment type of the user.
+	TemplateID string `json:"templateId" validate:"omitempty,uuid"`
+}
+type KitAsset struct {
+	ID           string `json:"id" db:"id"`
+	AssetType    string `json:"assetType" db:"type"`
+	SerialNumber string `json:"serialNumber" db:"serial_number"`
+}
+type KitShortage struct {
+	AssetType string `json:"assetType"`
+	Required  int    `json:"required"`
+	Available int    `json:"available"`
+}
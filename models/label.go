@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AssetLabel struct {
+	ID           string `json:"id" db:"id"`
+	AssetTag     string `json:"assetTag" db:"asset_tag"`
+	SerialNumber string `json:"serialNumber" db:"serial_number"`
+	Brand        string `json:"brand" db:"brand"`
+	Model        string `json:"model" db:"model"`
+}
+
+// LabelFilter selects the assets to print labels for; empty fields are not filtered on.
+type LabelFilter struct {
+	AssetIDs   []string
+	Type       string
+	Status     string
+	Owner      string
+	LocationID string
+}
+
+// AssetDetail is what a scanned label resolves to.
+type AssetDetail struct {
+	ID             string          `json:"id" db:"id"`
+	AssetTag       string          `json:"assetTag" db:"asset_tag"`
+	SerialNumber   string          `json:"serialNumber" db:"serial_number"`
+	Brand          string          `json:"brand" db:"brand"`
+	Model          string          `json:"model" db:"model"`
+	AssetType      string          `json:"type" db:"type"`
+	Status         string          `json:"status" db:"status"`
+	Owner          string          `json:"owner" db:"owner"`
+	AssignedTo     *string         `json:"assignedTo" db:"assigned_to"`
+	AssignedToName *string         `json:"assignedToName" db:"assigned_to_name"`
+	AssignedOn     *time.Time      `json:"assignedOn" db:"assigned_on"`
+	LocationID     *string         `json:"locationId" db:"location_id"`
+	Location       *Location       `json:"location"`
+	WarrantyStart  time.Time       `json:"warrantyStart" db:"warranty_start"`
+	WarrantyEnd    time.Time       `json:"warrantyEnd" db:"warranty_end"`
+	PurchaseDate   *time.Time      `json:"purchaseDate" db:"purchase_date"`
+	Specs          json.RawMessage `json:"specs" db:"specs"`
+	CustomFields   json.RawMessage `json:"customFields" db:"custom_fields"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	ArchivedAt     *time.Time      `json:"archivedAt" db:"archived_at"`
+}
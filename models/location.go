@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+type Location struct {
+	ID       string  `json:"id" db:"id"`
+	Name     string  `json:"name" db:"name"`
+	Kind     string  `json:"kind" db:"kind"`
+	ParentID *string `json:"parentId" db:"parent_id"`
+	Address  *string `json:"address" db:"address"`
+	// Path names the location from its site down, for instance "HQ / 2nd floor / Store room".
+	Path      string    `json:"path" db:"path"`
+	Assets    int       `json:"assets" db:"assets"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+type CreateLocationRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Kind     string `json:"kind" validate:"required,oneof=site floor room cabinet"`
+	ParentID string `json:"parentId" validate:"required_unless=Kind site,excluded_if=Kind site,omitempty,uuid"`
+	Address  string `json:"address" validate:"max=500"`
+}
+type UpdateLocationRequest struct {
+	Name    string `json:"name" validate:"required,max=100"`
+	Address string `json:"address" validate:"max=500"`
+}
+type MoveAssetRequest struct {
+	LocationID string `json:"locationId" validate:"required,uuid"`
+	Notes      string `json:"notes" validate:"max=500"`
+}
+type AssetMovement struct {
+	ID               string    `json:"id" db:"id"`
+	AssetID          string    `json:"assetId" db:"asset_id"`
+	SerialNumber     string    `json:"serialNumber" db:"serial_number"`
+	FromLocation     *string   `json:"fromLocation" db:"from_location"`
+	FromLocationName *string   `json:"fromLocationName" db:"from_location_name"`
+	ToLocation       *string   `json:"toLocation" db:"to_location"`
+	ToLocationName   *string   `json:"toLocationName" db:"to_location_name"`
+	FromSite         *string   `json:"fromSite" db:"from_site"`
+	ToSite           *string   `json:"toSite" db:"to_site"`
+	Reason           string    `json:"reason" db:"reason"`
+	MovedBy          string    `json:"movedBy" db:"moved_by"`
+	MovedAt          time.Time `json:"movedAt" db:"moved_at"`
+	Notes            *string   `json:"notes" db:"notes"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type UserMFA struct {
+	UserID       string     `db:"user_id"`
+	Ciphertext   []byte     `db:"ciphertext"`
+	WrappedKey   []byte     `db:"wrapped_key"`
+	KeyID        string     `db:"key_id"`
+	LastUsedStep int64      `db:"last_used_step"`
+	ConfirmedAt  *time.Time `db:"confirmed_at"`
+}
+type MFAEnrollment struct {
+	OtpauthURI string `json:"otpauthUri"`
+	// QRCode is a base64 encoded PNG of the otpauth URI.
+	QRCode string `json:"qrCode"`
+}
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfaToken" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recoveryCode" validate:"required_without=Code,omitempty,max=20"`
+}
+type MFAChallenge struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+	ExpiresIn   int    `json:"expiresIn"`
+}
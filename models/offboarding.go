@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+type Offboarding struct {
+	ID             string     `json:"id" db:"id"`
+	UserID         string     `json:"userId" db:"user_id"`
+	UserName       string     `json:"userName" db:"user_name"`
+	UserEmail      string     `json:"userEmail" db:"user_email"`
+	StartedBy      string     `json:"startedBy" db:"started_by"`
+	LastWorkingDay *time.Time `json:"lastWorkingDay" db:"last_working_day"`
+	Notes          *string    `json:"notes" db:"notes"`
+	Status         string     `json:"status" db:"status"`
+	StartedAt      time.Time  `json:"startedAt" db:"started_at"`
+	ClearedAt      *time.Time `json:"clearedAt" db:"cleared_at"`
+	PendingTasks   int        `json:"pendingTasks" db:"pending_tasks"`
+}
+
+// ReturnTask is an asset a leaver has to hand back. It resolves when the asset is returned
+// through the normal return flow or written off as lost.
+type ReturnTask struct {
+	ID              string     `json:"id" db:"id"`
+	OffboardingID   string     `json:"offboardingId" db:"offboarding_id"`
+	AssetID         string     `json:"assetId" db:"asset_id"`
+	SerialNumber    string     `json:"serialNumber" db:"serial_number"`
+	Brand           string     `json:"brand" db:"brand"`
+	Model           string     `json:"model" db:"model"`
+	AssetType       string     `json:"type" db:"type"`
+	AssignedFrom    time.Time  `json:"assignedFrom" db:"assigned_from"`
+	Status          string     `json:"status" db:"status"`
+	ReturnCondition *string    `json:"returnCondition" db:"return_condition"`
+	ResolvedBy      *string    `json:"resolvedBy" db:"resolved_by"`
+	ResolvedByName  *string    `json:"resolvedByName" db:"resolved_by_name"`
+	ResolvedAt      *time.Time `json:"resolvedAt" db:"resolved_at"`
+	Notes           *string    `json:"notes" db:"notes"`
+}
+type OffboardingDetail struct {
+	Offboarding
+	Tasks []ReturnTask `json:"tasks"`
+}
+type StartOffboardingRequest struct {
+	LastWorkingDay *time.Time `json:"lastWorkingDay"`
+	Notes          string     `json:"notes" validate:"max=2000"`
+}
+type WriteOffRequest struct {
+	Notes string `json:"notes" validate:"required,max=1000"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+type Reservation struct {
+	ID              string     `json:"id" db:"id"`
+	AssetID         string     `json:"assetId" db:"asset_id"`
+	SerialNumber    string     `json:"serialNumber" db:"serial_number"`
+	AssetType       string     `json:"assetType" db:"type"`
+	ReservedFor     string     `json:"reservedFor" db:"reserved_for"`
+	ReservedForName string     `json:"reservedForName" db:"reserved_for_name"`
+	ReservedBy      string     `json:"reservedBy" db:"reserved_by"`
+	StartsOn        time.Time  `json:"startsOn" db:"starts_on"`
+	ExpiresAt       time.Time  `json:"expiresAt" db:"expires_at"`
+	Notes           *string    `json:"notes" db:"notes"`
+	Status          string     `json:"status" db:"status"`
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
+	ClosedAt        *time.Time `json:"closedAt" db:"closed_at"`
+	ClosedBy        *string    `json:"closedBy" db:"closed_by"`
+}
+type ReserveAssetRequest struct {
+	UserID   string    `json:"userId" validate:"required,uuid"`
+	StartsOn time.Time `json:"startsOn" validate:"required"`
+	// ExpiresAt defaults to a week after the start date.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Notes     string     `json:"notes" validate:"max=1000"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Role struct {
+	Name        string         `json:"name" db:"name"`
+	Label       string         `json:"label" db:"label"`
+	MFARequired bool           `json:"mfaRequired" db:"mfa_required"`
+	Builtin     bool           `json:"builtin" db:"builtin"`
+	Permissions pq.StringArray `json:"permissions" db:"permissions"`
+	CreatedAt   time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt   *time.Time     `json:"updatedAt" db:"updated_at"`
+}
+type Permission struct {
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+}
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=3,max=50"`
+	Label       string   `json:"label" validate:"required,max=100"`
+	MFARequired bool     `json:"mfaRequired"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
+type UpdateRoleRequest struct {
+	Label       string   `json:"label" validate:"required,max=100"`
+	MFARequired bool     `json:"mfaRequired"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
+
+// SessionPermissions is what a session may do, derived from the role of its user.
+type SessionPermissions struct {
+	Role        string `db:"role"`
+	MFARequired bool   `db:"mfa_required"`
+	// EmailVerified is false until the user confirms their email address.
+	EmailVerified bool           `db:"email_verified"`
+	Permissions   pq.StringArray `db:"permissions"`
+}
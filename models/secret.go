@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AssetSecret struct {
+	ID         string `db:"id"`
+	AssetID    string `db:"asset_id"`
+	Field      string `db:"field"`
+	Ciphertext []byte `db:"ciphertext"`
+	WrappedKey []byte `db:"wrapped_key"`
+	KeyID      string `db:"key_id"`
+}
+type RevealSecretRequest struct {
+	Field  string `json:"field" validate:"omitempty,max=50"`
+	Reason string `json:"reason" validate:"required,min=5,max=500"`
+}
+type SecretAccess struct {
+	ID         string    `json:"id" db:"id"`
+	AssetID    string    `json:"assetId" db:"asset_id"`
+	Field      string    `json:"field" db:"field"`
+	AccessedBy string    `json:"accessedBy" db:"accessed_by"`
+	Name       string    `json:"accessedByName" db:"accessed_by_name"`
+	SessionID  *string   `json:"sessionId" db:"session_id"`
+	Reason     string    `json:"reason" db:"reason"`
+	IPAddress  *string   `json:"ipAddress" db:"ip_address"`
+	AccessedAt time.Time `json:"accessedAt" db:"accessed_at"`
+}
+type AssetSpecs struct {
+	ID    string          `db:"id"`
+	Specs json.RawMessage `db:"specs"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MyAsset is an asset as shown to the employee it is assigned to. Specs never contain secrets,
+// they are stored apart from the specs.
+type MyAsset struct {
+	ID             string          `json:"id" db:"id"`
+	Brand          string          `json:"brand" db:"brand"`
+	Model          string          `json:"model" db:"model"`
+	SerialNumber   string          `json:"serialNumber" db:"serial_number"`
+	AssetType      string          `json:"type" db:"type"`
+	Status         string          `json:"status" db:"status"`
+	WarrantyEnd    time.Time       `json:"warrantyEnd" db:"warranty_end"`
+	Specs          json.RawMessage `json:"specs" db:"specs"`
+	AssignedFrom   time.Time       `json:"assignedFrom" db:"assigned_from"`
+	AcknowledgedAt *time.Time      `json:"acknowledgedAt" db:"acknowledged_at"`
+}
+type AssetIssue struct {
+	ID              string     `json:"id" db:"id"`
+	AssetID         string     `json:"assetId" db:"asset_id"`
+	SerialNumber    string     `json:"serialNumber" db:"serial_number"`
+	ReportedBy      string     `json:"reportedBy" db:"reported_by"`
+	ReportedByName  string     `json:"reportedByName" db:"reported_by_name"`
+	Kind            string     `json:"kind" db:"kind"`
+	Description     string     `json:"description" db:"description"`
+	Status          string     `json:"status" db:"status"`
+	ResolvedBy      *string    `json:"resolvedBy" db:"resolved_by"`
+	ResolvedAt      *time.Time `json:"resolvedAt" db:"resolved_at"`
+	ResolutionNotes *string    `json:"resolutionNotes" db:"resolution_notes"`
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
+}
+type ReportIssueRequest struct {
+	Kind        string `json:"kind" validate:"required,oneof=lost broken needs_repair"`
+	Description string `json:"description" validate:"required,max=1000"`
+}
+type ResolveIssueRequest struct {
+	Status string `json:"status" validate:"required,oneof=resolved dismissed"`
+	Notes  string `json:"notes" validate:"max=1000"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+type ServiceTicket struct {
+	ID               string     `json:"id" db:"id"`
+	AssetID          string     `json:"assetID" db:"asset_id"`
+	SerialNumber     string     `json:"serialNumber" db:"serial_number"`
+	FaultDescription string     `json:"faultDescription" db:"fault_description"`
+	VendorID         *string    `json:"vendorId" db:"vendor_id"`
+	VendorName       *string    `json:"vendorName" db:"vendor_name"`
+	ExpectedReturn   *time.Time `json:"expectedReturn" db:"expected_return"`
+	CostEstimate     *float64   `json:"costEstimate" db:"cost_estimate"`
+	InvoiceAmount    *float64   `json:"invoiceAmount" db:"invoice_amount"`
+	Status           string     `json:"status" db:"status"`
+	Outcome          *string    `json:"outcome" db:"outcome"`
+	ResolutionNotes  *string    `json:"resolutionNotes" db:"resolution_notes"`
+	OpenedBy         *string    `json:"openedBy" db:"opened_by"`
+	OpenedAt         time.Time  `json:"openedAt" db:"opened_at"`
+	ClosedBy         *string    `json:"closedBy" db:"closed_by"`
+	ClosedAt         *time.Time `json:"closedAt" db:"closed_at"`
+}
+type OpenServiceTicketRequest struct {
+	FaultDescription string     `json:"faultDescription" validate:"required,max=2000"`
+	VendorID         string     `json:"vendorId" validate:"omitempty,uuid"`
+	ExpectedReturn   *time.Time `json:"expectedReturn"`
+	CostEstimate     *float64   `json:"costEstimate" validate:"omitempty,gte=0"`
+}
+type UpdateServiceTicketRequest struct {
+	Status         string     `json:"status" validate:"omitempty,oneof=in_progress awaiting_parts"`
+	VendorID       *string    `json:"vendorId" validate:"omitempty,uuid"`
+	ExpectedReturn *time.Time `json:"expectedReturn"`
+	CostEstimate   *float64   `json:"costEstimate" validate:"omitempty,gte=0"`
+}
+type CloseServiceTicketRequest struct {
+	InvoiceAmount *float64 `json:"invoiceAmount" validate:"required,gte=0"`
+	Outcome       string   `json:"outcome" validate:"required,oneof=available damaged"`
+	Notes         string   `json:"notes" validate:"max=2000"`
+}
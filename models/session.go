@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID         string     `json:"id" db:"id"`
+	UserAgent  *string    `json:"userAgent" db:"user_agent"`
+	IPAddress  *string    `json:"ipAddress" db:"ip_address"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastSeenAt *time.Time `json:"lastSeenAt" db:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expiresAt" db:"expires_at"`
+	Current    bool       `json:"current" db:"-"`
+}
+type RefreshToken struct {
+	ID               string     `db:"id"`
+	SessionID        string     `db:"session_id"`
+	UserID           string     `db:"user_id"`
+	Role             string     `db:"role"`
+	ExpiresAt        time.Time  `db:"expires_at"`
+	UsedAt           *time.Time `db:"used_at"`
+	SessionExpiresAt time.Time  `db:"session_expires_at"`
+	SessionRevokedAt *time.Time `db:"session_archived_at"`
+}
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+type AssetTransfer struct {
+	ID            string     `json:"id" db:"id"`
+	AssetID       string     `json:"assetId" db:"asset_id"`
+	SerialNumber  string     `json:"serialNumber" db:"serial_number"`
+	AssetType     string     `json:"assetType" db:"type"`
+	FromUser      string     `json:"fromUser" db:"from_user"`
+	FromUserName  string     `json:"fromUserName" db:"from_user_name"`
+	ToUser        string     `json:"toUser" db:"to_user"`
+	ToUserName    string     `json:"toUserName" db:"to_user_name"`
+	Message       *string    `json:"message" db:"message"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
+	AcceptedAt    *time.Time `json:"acceptedAt" db:"accepted_at"`
+	DecidedBy     *string    `json:"decidedBy" db:"decided_by"`
+	DecidedAt     *time.Time `json:"decidedAt" db:"decided_at"`
+	DecisionNotes *string    `json:"decisionNotes" db:"decision_notes"`
+	ClosedAt      *time.Time `json:"closedAt" db:"closed_at"`
+}
+type TransferRequest struct {
+	ToUserID string `json:"toUserId" validate:"required,uuid"`
+	Message  string `json:"message" validate:"max=1000"`
+}
+type DecideTransferRequest struct {
+	Notes string `json:"notes" validate:"max=1000"`
+}
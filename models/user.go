@@ -1,16 +1,37 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type RegisterUser struct {
 	Name        string `json:"name" db:"name" validate:"required,min=3,max=50"`
 	Email       string `json:"email" db:"email" validate:"required,email"`
-	Role        string `json:"role" db:"role" validate:"required,oneof=admin employee project_manager asset_manager employee_manager"`
-	Type        string `json:"type" db:"type" validate:"required,oneof=full_time intern freelancer" `
+	Type        string `json:"type" db:"type" validate:"required,oneof=full-time intern freelancer" `
 	PhoneNumber string `json:"phoneNumber" db:"phone_number" validate:"required,len=10"`
 	Password    string `json:"password" db:"password" validate:"required,min=8,max=20"`
+
+	CustomFields json.RawMessage `json:"customFields" db:"custom_fields"`
+}
+
+// InviteUserRequest creates an account for someone who sets their own password through the
+// emailed invitation.
+type InviteUserRequest struct {
+	Name        string `json:"name" validate:"required,min=3,max=50"`
+	Email       string `json:"email" validate:"required,email"`
+	Role        string `json:"role" validate:"required,max=50"`
+	Type        string `json:"type" validate:"required,oneof=full-time intern freelancer"`
+	PhoneNumber string `json:"phoneNumber" validate:"required,len=10"`
+
+	CustomFields json.RawMessage `json:"customFields"`
+}
+type AcceptInviteRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=20"`
+}
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,max=50"`
 }
 
 type LoginUser struct {
@@ -23,9 +44,10 @@ type LoginUser struct {
 //		Password string `json:"password" db:"password"`
 //	}
 type UserCtx struct {
-	UserID    string `json:"userID"`
-	SessionID string `json:"sessionID"`
-	Role      string `json:"role"`
+	UserID      string `json:"userID"`
+	SessionID   string `json:"sessionID"`
+	Role        string `json:"role"`
+	MFAVerified bool   `json:"mfaVerified"`
 }
 type User struct {
 	ID          string     `json:"id" db:"id"`
@@ -36,68 +58,53 @@ type User struct {
 	Employment  string     `json:"employment" db:"employment"`
 	Password    string     `json:"password" db:"password"`
 	CreatedAt   *time.Time `db:"created_at"`
-	ArchivedAt  *time.Time `db:"archived_at"`
+	// EmailVerifiedAt is set once the user follows the link mailed on registration.
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	ArchivedAt      *time.Time `db:"archived_at"`
 }
 type Asset struct {
 	Brand         string    `json:"brand" db:"brand" validate:"required"`
 	Model         string    `json:"model" db:"model" validate:"required"`
 	SerialNumber  string    `json:"serialNumber" db:"serial_number" validate:"required"`
-	AssetType     string    `json:"assetType" db:"type" validate:"required,oneof=laptop keyboard mouse mobile"`
+	AssetType     string    `json:"assetType" db:"type" validate:"required"`
 	Status        string    `json:"status" db:"status" validate:"required,oneof=available assigned in_service for_repair damaged"`
 	Owner         string    `json:"owner" db:"owner" validate:"required,oneof=client remotestate"`
 	WarrantyStart time.Time `json:"warrantyStart" db:"warranty_start" validate:"required"`
 	WarrantyEnd   time.Time `json:"warrantyEnd" db:"warranty_end" validate:"required"`
 
-	Laptop   LaptopSpecs   `json:"laptopSpecs,omitempty"`
-	Keyboard KeyboardSpecs `json:"keyboardSpecs,omitempty"`
-	Mouse    MouseSpecs    `json:"mouseSpecs,omitempty"`
-	Mobile   MobileSpecs   `json:"mobileSpecs,omitempty"`
+	PurchaseVendorID *string    `json:"purchaseVendorId" db:"purchase_vendor_id" validate:"omitempty,uuid"`
+	PurchaseDate     *time.Time `json:"purchaseDate" db:"purchase_date"`
+	PurchasePrice    *float64   `json:"purchasePrice" db:"purchase_price" validate:"omitempty,gte=0"`
+	Currency         string     `json:"currency" db:"currency" validate:"required_with=PurchasePrice,omitempty,iso4217"`
+	InvoiceNumber    string     `json:"invoiceNumber" db:"invoice_number" validate:"max=100"`
+	LocationID       *string    `json:"locationId" db:"location_id" validate:"omitempty,uuid"`
+
+	// Specs holds the type specific fields, validated against the spec schema of the asset type.
+	Specs        json.RawMessage `json:"specs" db:"specs"`
+	CustomFields json.RawMessage `json:"customFields" db:"custom_fields"`
 }
 
 type AssetInfo struct {
-	Brand        string    `json:"brand" db:"brand"`
-	Model        string    `json:"model" db:"model"`
-	AssetType    string    `json:"type" db:"type"`
-	SerialNumber string    `json:"serialNumber" db:"serial_number"`
-	AssetStatus  string    `json:"assetStatus" db:"status"`
-	AssignedTo   string    `json:"assignedTo" db:"assigned_to"`
-	Owner        string    `json:"owner" db:"owner"`
-	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
-}
-type LaptopSpecs struct {
-	AssetID         string `json:"assetID" db:"asset_id"`
-	Processor       string `json:"processor" db:"processor"`
-	Ram             string `json:"ram" db:"ram"`
-	Storage         string `json:"storage" db:"storage"`
-	OperatingSystem string `json:"operatingSystem" db:"operating_system"`
-	Charger         string `json:"charger" db:"charger"`
-	DevicePassword  string `json:"devicePassword" db:"device_password"`
-}
-type KeyboardSpecs struct {
-	AssetID      string `json:"assetID" db:"asset_id"`
-	Layout       string `json:"layout" db:"layout"`
-	Connectivity string `json:"connectivity" db:"connectivity"`
-}
-type MouseSpecs struct {
-	AssetID      string `json:"assetID" db:"asset_id"`
-	Dpi          int    `json:"dpi" db:"dpi"`
-	Connectivity string `json:"connectivity" db:"connectivity"`
-}
-type MobileSpecs struct {
-	AssetID         string `json:"assetID" db:"asset_id"`
-	OperatingSystem string `json:"operatingSystem" db:"operating_system"`
-	Ram             string `json:"ram" db:"ram"`
-	Storage         string `json:"storage" db:"storage"`
-	Charger         string `json:"charger" db:"charger"`
-	DevicePassword  string `json:"devicePassword" db:"device_password"`
+	Brand        string          `json:"brand" db:"brand"`
+	Model        string          `json:"model" db:"model"`
+	AssetType    string          `json:"type" db:"type"`
+	SerialNumber string          `json:"serialNumber" db:"serial_number"`
+	AssetTag     string          `json:"assetTag" db:"asset_tag"`
+	AssetStatus  string          `json:"assetStatus" db:"status"`
+	AssignedTo   string          `json:"assignedTo" db:"assigned_to"`
+	Owner        string          `json:"owner" db:"owner"`
+	LocationID   *string         `json:"locationId" db:"location_id"`
+	CustomFields json.RawMessage `json:"customFields" db:"custom_fields"`
+	CreatedAt    time.Time       `json:"createdAt" db:"created_at"`
 }
 type AssignedAsset struct {
-	AssignedTo string `json:"assignedTo" db:"assigned_to"`
+	AssignedTo string `json:"assignedTo" db:"assigned_to" validate:"required,uuid"`
 }
-type ServiceAsset struct {
-	ServiceStart time.Time `json:"serviceStart" db:"service_start"`
-	ServiceEnd   time.Time `json:"serviceEnd" db:"service_end"`
-	ReturnedOn   time.Time `json:"returnedOn" db:"returned_on"`
+type ReturnAsset struct {
+	Condition string `json:"condition" db:"return_condition" validate:"required,oneof=good fair needs_repair damaged"`
+	Notes     string `json:"notes" db:"return_notes" validate:"max=500"`
+	// LocationID defaults to where the asset was kept before it was assigned.
+	LocationID string `json:"locationId" validate:"omitempty,uuid"`
 }
 type DeleteAsset struct {
 	ArchivedBy string `json:"archivedBy" db:"archived_by"`
@@ -109,6 +116,7 @@ type DashboardSummary struct {
 	WaitingForRepair int `json:"waitingForRepair" db:"waiting_for_repair"`
 	InService        int `json:"inService" db:"in_service"`
 	Damaged          int `json:"damaged" db:"damaged"`
+	Reserved         int `json:"reserved" db:"reserved"`
 }
 
 type DashboardData struct {
@@ -123,6 +131,7 @@ type UserInfoRequest struct {
 	Role         string             `json:"role" db:"role" validate:"required"`
 	Type         string             `json:"type" db:"type" validate:"required"`
 	CreatedAt    string             `json:"createdAt" db:"created_at" validate:"required"`
+	CustomFields json.RawMessage    `json:"customFields" db:"custom_fields"`
 	AssetDetails []AssetInfoRequest `json:"assetDetails"`
 }
 type AssetInfoRequest struct {
@@ -136,13 +145,36 @@ type UpdateAssetRequest struct {
 	Brand         string    `json:"brand" validate:"required"`
 	Model         string    `json:"model" validate:"required"`
 	SerialNo      string    `json:"serialNo" validate:"required"`
-	Type          string    `json:"type" validate:"required" validate:"required,oneof=laptop keyboard mouse mobile"`
+	Type          string    `json:"type" validate:"required"`
 	Owner         string    `json:"owner" validate:"required" validate:"required,oneof=client remotestate"`
 	WarrantyStart time.Time `json:"warrantyStart" validate:"required" validate:"required"`
 	WarrantyEnd   time.Time `json:"warrantyEnd" validate:"required" validate:"required"`
 
-	Laptop   *LaptopSpecs   `json:"laptop,omitempty"`
-	Mouse    *MouseSpecs    `json:"mouse,omitempty"`
-	Keyboard *KeyboardSpecs `json:"keyboard,omitempty"`
-	Mobile   *MobileSpecs   `json:"mobile,omitempty"`
+	PurchaseVendorID *string    `json:"purchaseVendorId" validate:"omitempty,uuid"`
+	PurchaseDate     *time.Time `json:"purchaseDate"`
+	PurchasePrice    *float64   `json:"purchasePrice" validate:"omitempty,gte=0"`
+	Currency         string     `json:"currency" validate:"required_with=PurchasePrice,omitempty,iso4217"`
+	InvoiceNumber    string     `json:"invoiceNumber" validate:"max=100"`
+
+	Specs        json.RawMessage `json:"specs"`
+	CustomFields json.RawMessage `json:"customFields"`
+}
+type AssignmentHistory struct {
+	ID              string     `json:"id" db:"id"`
+	AssetID         string     `json:"assetID" db:"asset_id"`
+	SerialNumber    string     `json:"serialNumber" db:"serial_number"`
+	Brand           string     `json:"brand" db:"brand"`
+	Model           string     `json:"model" db:"model"`
+	AssignedTo      string     `json:"assignedTo" db:"assigned_to"`
+	AssignedToName  string     `json:"assignedToName" db:"assigned_to_name"`
+	AssignedBy      *string    `json:"assignedBy" db:"assigned_by_id"`
+	AssignedFrom    time.Time  `json:"assignedFrom" db:"assigned_from"`
+	AssignedUntil   *time.Time `json:"assignedUntil" db:"assigned_until"`
+	ReceivedBy      *string    `json:"receivedBy" db:"received_by_id"`
+	ReturnCondition *string    `json:"returnCondition" db:"return_condition"`
+	ReturnNotes     *string    `json:"returnNotes" db:"return_notes"`
+	AcknowledgedAt  *time.Time `json:"acknowledgedAt" db:"acknowledged_at"`
+}
+type AssetStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=available assigned in_service for_repair damaged"`
 }
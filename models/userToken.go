@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type UserToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=20"`
+}
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Vendor struct {
+	ID          string     `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	ContactName *string    `json:"contactName" db:"contact_name"`
+	Email       *string    `json:"email" db:"email"`
+	PhoneNumber *string    `json:"phoneNumber" db:"phone_no"`
+	SLADays     *int       `json:"slaDays" db:"sla_days"`
+	Notes       *string    `json:"notes" db:"notes"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   *time.Time `json:"updatedAt" db:"updated_at"`
+}
+type VendorRequest struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	ContactName string `json:"contactName" validate:"max=200"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	PhoneNumber string `json:"phoneNumber" validate:"max=20"`
+	SLADays     *int   `json:"slaDays" validate:"omitempty,gt=0"`
+	Notes       string `json:"notes" validate:"max=2000"`
+}
+type VendorReport struct {
+	ID                  string          `json:"id" db:"id"`
+	Name                string          `json:"name" db:"name"`
+	SLADays             *int            `json:"slaDays" db:"sla_days"`
+	AssetsPurchased     int             `json:"assetsPurchased" db:"assets_purchased"`
+	PurchaseSpend       json.RawMessage `json:"purchaseSpend" db:"purchase_spend"`
+	TicketsOpened       int             `json:"ticketsOpened" db:"tickets_opened"`
+	TicketsClosed       int             `json:"ticketsClosed" db:"tickets_closed"`
+	RepairSpend         float64         `json:"repairSpend" db:"repair_spend"`
+	AvgTurnaroundDays   *float64        `json:"avgTurnaroundDays" db:"avg_turnaround_days"`
+	MaxTurnaroundDays   *float64        `json:"maxTurnaroundDays" db:"max_turnaround_days"`
+	TicketsBreachingSLA int             `json:"ticketsBreachingSla" db:"tickets_breaching_sla"`
+}
@@ -25,25 +25,228 @@ func SetupRoutes() *Server {
 		//public routes
 		v1.Post("/register", handler.RegisterUser)
 		v1.Post("/login", handler.LoginUser)
+		v1.Post("/login/mfa", handler.LoginMFA)
+		v1.Post("/token/refresh", handler.RefreshToken)
+		v1.Post("/password/forgot", handler.ForgotPassword)
+		v1.Post("/password/reset", handler.ResetPassword)
+		v1.Post("/email/verify", handler.VerifyEmail)
+		v1.Post("/invite/accept", handler.AcceptInvite)
 		// auth required
 		v1.Group(func(v1 chi.Router) {
 			v1.Use(middleware.Auth)
 			v1.Delete("/logout", handler.Logout)
+			v1.Get("/sessions", handler.ListSessions)
+			v1.Delete("/sessions", handler.RevokeOtherSessions)
+			v1.Delete("/sessions/{id}", handler.RevokeSession)
+			v1.Post("/email/resend-verification", handler.ResendEmailVerification)
+			// two-factor authentication
+			v1.Post("/mfa/enroll", handler.EnrollMFA)
+			v1.Post("/mfa/confirm", handler.ConfirmMFA)
+			v1.Post("/mfa/recovery-codes", handler.RegenerateRecoveryCodes)
+			v1.Delete("/mfa", handler.DisableMFA)
 			v1.Get("/user/{id}", handler.FetchUser)
+			// self service
+			v1.Route("/me", func(me chi.Router) {
+				me.Get("/assets", handler.MyAssets)
+				me.Get("/asset-history", handler.MyAssetHistory)
+				me.Put("/assets/{id}/acknowledge", handler.AcknowledgeAsset)
+				me.Post("/assets/{id}/issues", handler.ReportAssetIssue)
+				me.Get("/issues", handler.MyIssues)
+				me.Post("/asset-requests", handler.CreateAssetRequest)
+				me.Get("/asset-requests", handler.MyAssetRequests)
+				me.Put("/asset-requests/{id}/cancel", handler.CancelAssetRequest)
+				me.Get("/reservations", handler.MyReservations)
+				me.Post("/assets/{id}/transfers", handler.InitiateTransfer)
+				me.Get("/transfers", handler.MyTransfers)
+				me.Put("/transfers/{id}/accept", handler.AcceptTransfer)
+				me.Put("/transfers/{id}/decline", handler.DeclineTransfer)
+				me.Put("/transfers/{id}/cancel", handler.CancelTransfer)
+			})
+			// asset requests, approvers are resolved per request rather than by permission
+			v1.Get("/asset-requests/approvals", handler.PendingApprovals)
+			v1.Put("/asset-requests/{id}/approve", handler.ApproveAssetRequest)
+			v1.Put("/asset-requests/{id}/reject", handler.RejectAssetRequest)
+			v1.Get("/asset-requests/{id}", handler.GetAssetRequest)
+			v1.With(middleware.RequirePermission("asset_request.read")).Get("/asset-requests", handler.ListAssetRequests)
+			v1.With(middleware.RequirePermission("asset_request.fulfil")).Put("/asset-requests/{id}/fulfil", handler.FulfilAssetRequest)
+			v1.With(middleware.RequirePermission("asset_request.read")).Get("/asset-request-steps", handler.ListApprovalSteps)
+			v1.With(middleware.RequirePermission("asset_request.configure")).Put("/asset-request-steps", handler.ReplaceApprovalSteps)
+			// permission based
+			v1.With(middleware.RequirePermission("asset.create")).Post("/asset", handler.CreateAsset)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/assets", handler.ShowAssets)
+			v1.With(middleware.RequirePermission("asset.assign")).Put("/assign-assets/{id}", handler.AssignedAssets)
+			v1.With(middleware.RequirePermission("asset.assign")).Put("/return-asset/{id}", handler.ReturnAsset)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/assets/{id}/timeline", handler.AssetTimeline)
+			v1.With(middleware.RequirePermission("user.read")).Get("/users/{id}/asset-history", handler.UserAssetHistory)
+			v1.With(middleware.RequirePermission("asset.update")).Put("/assets/{id}/status", handler.ChangeAssetStatus)
+			v1.With(middleware.RequirePermission("asset.update")).Put("/update-asset/{id}", handler.UpdateAsset)
+			//delete assets
+			v1.With(middleware.RequirePermission("asset.delete")).Put("/delete-asset/{id}", handler.DeleteAsset)
+			// show archived assets also
+			// repairs
 			v1.Group(func(v1 chi.Router) {
-				v1.Use(middleware.RoleMiddleware("admin", "asset-manager"))
-				// role based
-				v1.Post("/asset", handler.CreateAsset)
-				v1.Get("/assets", handler.ShowAssets)
-				v1.Put("/assign-assets/{id}", handler.AssignedAssets)
-				v1.Put("/service-assets/{id}", handler.ServiceAssets)
-				//delete assets
-				v1.Put("/delete-asset/{id}", handler.DeleteAsset)
-				v1.Get("/user-info", handler.GetAllUsers)
-				v1.Put("/update-asset", handler.UpdateAsset)
-				// show archived assets also
+				v1.Use(middleware.RequirePermission("repair.manage"))
+				v1.Post("/assets/{id}/service-tickets", handler.OpenServiceTicket)
+				v1.Get("/service-tickets", handler.ListServiceTickets)
+				v1.Get("/service-tickets/overdue", handler.OverdueServiceTickets)
+				v1.Get("/service-tickets/{id}", handler.GetServiceTicket)
+				v1.Put("/service-tickets/{id}", handler.UpdateServiceTicket)
+				v1.Put("/service-tickets/{id}/close", handler.CloseServiceTicket)
+			})
+			// reported issues
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("issue.manage"))
+				v1.Get("/asset-issues", handler.ListAssetIssues)
+				v1.Put("/asset-issues/{id}/resolve", handler.ResolveAssetIssue)
+			})
+			// asset types
+			v1.With(middleware.RequirePermission("asset.read")).Get("/asset-types", handler.ListAssetTypes)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/asset-types/{name}", handler.GetAssetType)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("asset_type.manage"))
+				v1.Post("/asset-types", handler.CreateAssetType)
+				v1.Put("/asset-types/{name}", handler.UpdateAssetType)
+				v1.Delete("/asset-types/{name}", handler.DeleteAssetType)
+			})
+			// custom fields
+			v1.Get("/custom-fields", handler.ListCustomFields)
+			v1.With(middleware.RequirePermission("user.update")).Put("/users/{id}/custom-fields", handler.UpdateUserCustomFields)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("custom_field.manage"))
+				v1.Post("/custom-fields", handler.CreateCustomField)
+				v1.Put("/custom-fields/{id}", handler.UpdateCustomField)
+				v1.Delete("/custom-fields/{id}", handler.DeleteCustomField)
+			})
+			// depreciation
+			v1.With(middleware.RequirePermission("asset.read")).Get("/assets/{id}/book-value", handler.AssetBookValue)
+			v1.With(middleware.RequirePermission("report.export")).Get("/reports/depreciation", handler.DepreciationReport)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/depreciation-policies", handler.ListDepreciationPolicies)
+			v1.With(middleware.RequirePermission("depreciation.manage")).Put("/depreciation-policies/{type}", handler.UpdateDepreciationPolicy)
+			// vendors
+			v1.With(middleware.RequirePermission("vendor.read")).Get("/vendors", handler.ListVendors)
+			v1.With(middleware.RequirePermission("report.export")).Get("/vendors/report", handler.VendorReport)
+			v1.With(middleware.RequirePermission("vendor.read")).Get("/vendors/{id}", handler.GetVendor)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("vendor.manage"))
+				v1.Post("/vendors", handler.CreateVendor)
+				v1.Put("/vendors/{id}", handler.UpdateVendor)
+				v1.Delete("/vendors/{id}", handler.DeleteVendor)
+			})
+			// secrets
+			v1.With(middleware.RequirePermission("asset.secret.reveal")).Post("/assets/{id}/reveal-password", handler.RevealAssetPassword)
+			v1.With(middleware.RequirePermission("asset.secret.reveal")).Get("/assets/{id}/secret-access", handler.ListSecretAccess)
+			v1.With(middleware.RequirePermission("secret.rotate")).Post("/secrets/rotate", handler.RotateSecretKeys)
+			// users
+			v1.With(middleware.RequirePermission("user.read")).Get("/user-info", handler.GetAllUsers)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("user.manage"))
+				v1.Post("/users/invite", handler.InviteUser)
+				v1.Post("/users/{id}/invite", handler.ResendInvite)
+				v1.Put("/users/{id}/role", handler.ChangeUserRole)
+				v1.Put("/users/{id}/manager", handler.ChangeUserManager)
+				v1.Put("/users/{id}/archive", handler.ArchiveUser)
+				v1.Put("/users/{id}/unarchive", handler.UnarchiveUser)
+				v1.Delete("/users/{id}/mfa", handler.ResetUserMFA)
+			})
+			// reservations
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("asset.assign"))
+				v1.Post("/assets/{id}/reservations", handler.ReserveAsset)
+				v1.Get("/reservations", handler.ListReservations)
+				v1.Put("/reservations/{id}/cancel", handler.CancelReservation)
+			})
+			// transfers
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("transfer.approve"))
+				v1.Get("/transfers", handler.ListTransfers)
+				v1.Put("/transfers/{id}/approve", handler.ApproveTransfer)
+				v1.Put("/transfers/{id}/reject", handler.RejectTransfer)
+			})
+			// lost and stolen assets
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("incident.manage"))
+				v1.Post("/assets/{id}/incidents", handler.ReportIncident)
+				v1.Get("/assets/{id}/incidents", handler.AssetIncidents)
+				v1.Put("/assets/{id}/recover", handler.RecoverAsset)
+				v1.Get("/incidents", handler.ListIncidents)
+				v1.Get("/secret-rotation-reminders", handler.ListRotationReminders)
+				v1.Put("/secret-rotation-reminders/{id}/done", handler.CompleteRotationReminder)
+			})
+			// disposals, an asset leaves the company once its disposal is approved and completed
+			v1.With(middleware.RequirePermission("report.export")).Get("/reports/disposals", handler.DisposalReport)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("disposal.request"))
+				v1.Post("/assets/{id}/disposals", handler.RequestDisposal)
+				v1.Get("/disposals", handler.ListDisposals)
+				v1.Get("/disposals/{id}", handler.GetDisposal)
+				v1.Put("/disposals/{id}/cancel", handler.CancelDisposal)
+				v1.Put("/disposals/{id}/complete", handler.CompleteDisposal)
+				v1.Put("/disposals/{id}/certificate", handler.UploadDisposalCertificate)
+				v1.Get("/disposals/{id}/certificate", handler.DownloadDisposalCertificate)
+			})
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("disposal.approve"))
+				v1.Put("/disposals/{id}/approve", handler.ApproveDisposal)
+				v1.Put("/disposals/{id}/reject", handler.RejectDisposal)
+			})
+			// labels
+			v1.With(middleware.RequirePermission("asset.read")).Get("/assets/{id}/label", handler.AssetLabel)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/asset-labels", handler.LabelSheet)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/scan/{code}", handler.ScanAsset)
+			// locations, assets in stock are kept at one and every change is recorded as a movement
+			v1.With(middleware.RequirePermission("asset.read")).Get("/locations", handler.ListLocations)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/locations/{id}", handler.GetLocation)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/assets/{id}/movements", handler.AssetMovements)
+			v1.With(middleware.RequirePermission("asset.read")).Get("/asset-movements", handler.ListMovements)
+			v1.With(middleware.RequirePermission("asset.update")).Put("/assets/{id}/location", handler.MoveAsset)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("location.manage"))
+				v1.Post("/locations", handler.CreateLocation)
+				v1.Put("/locations/{id}", handler.UpdateLocation)
+				v1.Delete("/locations/{id}", handler.ArchiveLocation)
+			})
+			// audit campaigns
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("audit.perform"))
+				v1.Get("/audits", handler.ListAudits)
+				v1.Get("/audits/{id}", handler.GetAudit)
+				v1.Get("/audits/{id}/items", handler.ListAuditItems)
+				v1.Put("/audits/{id}/results", handler.RecordAuditResults)
+				v1.Get("/audits/{id}/report", handler.AuditReport)
+			})
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("audit.manage"))
+				v1.Post("/audits", handler.OpenAudit)
+				v1.Put("/audits/{id}/close", handler.CloseAudit)
+			})
+			// kits
+			v1.With(middleware.RequirePermission("asset.assign")).Post("/users/{id}/assign-kit", handler.AssignKit)
+			v1.With(middleware.RequirePermission("asset.assign")).Get("/kit-templates", handler.ListKitTemplates)
+			v1.With(middleware.RequirePermission("asset.assign")).Get("/kit-templates/{id}", handler.GetKitTemplate)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("kit.manage"))
+				v1.Post("/kit-templates", handler.CreateKitTemplate)
+				v1.Put("/kit-templates/{id}", handler.UpdateKitTemplate)
+				v1.Delete("/kit-templates/{id}", handler.DeleteKitTemplate)
+			})
+			// offboarding, assets come back through the return endpoint or are written off here
+			v1.With(middleware.RequirePermission("offboarding.manage")).Post("/users/{id}/offboarding", handler.StartOffboarding)
+			v1.With(middleware.RequirePermission("offboarding.manage")).Put("/offboardings/{id}/tasks/{taskId}/write-off", handler.WriteOffReturnTask)
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("offboarding.read"))
+				v1.Get("/offboardings", handler.ListOffboardings)
+				v1.Get("/offboardings/{id}", handler.GetOffboarding)
+				v1.Get("/offboardings/{id}/clearance-report", handler.ClearanceReport)
+			})
+			// roles
+			v1.Group(func(v1 chi.Router) {
+				v1.Use(middleware.RequirePermission("role.manage"))
+				v1.Get("/permissions", handler.ListPermissions)
+				v1.Get("/roles", handler.ListRoles)
+				v1.Post("/roles", handler.CreateRole)
+				v1.Put("/roles/{name}", handler.UpdateRole)
+				v1.Delete("/roles/{name}", handler.DeleteRole)
 			})
-
 		})
 
 	})
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/mailer"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+var (
+	ErrInvalidUserToken     = errors.New("invalid or expired token")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+)
+
+const (
+	PasswordResetTTL     = time.Hour
+	EmailVerificationTTL = 48 * time.Hour
+)
+
+// appLink builds a link into the frontend, which is served from APP_BASE_URL.
+func appLink(path, token string) string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return base + path + "?token=" + token
+}
+
+func issueUserToken(userID, purpose string, ttl time.Duration) (string, error) {
+	token, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		return dbHelper.CreateUserToken(tx, userID, purpose, tokenHash, time.Now().Add(ttl))
+	})
+	return token, txErr
+}
+
+// consumeUserToken marks a token as used, so it can only be redeemed once.
+func consumeUserToken(tx *sqlx.Tx, purpose, token string) (models.UserToken, error) {
+	userToken, err := dbHelper.GetUserTokenForUpdate(tx, purpose, utils.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return userToken, ErrInvalidUserToken
+	}
+	if err != nil {
+		return userToken, err
+	}
+	if userToken.UsedAt != nil || time.Now().After(userToken.ExpiresAt) {
+		return userToken, ErrInvalidUserToken
+	}
+	return userToken, dbHelper.MarkUserTokenUsed(tx, userToken.ID)
+}
+
+// RequestPasswordReset mails a reset link. Unknown emails are ignored without an error. The
+// handler runs it after responding, so neither the status nor the timing of the response
+// reveals which emails have an account.
+func RequestPasswordReset(email string) error {
+	user, err := dbHelper.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, err := issueUserToken(user.ID, "password_reset", PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	return mailer.Default().Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your storex password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s\n\nIf you did not ask for this, you can ignore this email.",
+			user.Name, PasswordResetTTL, appLink("/reset-password", token)),
+	})
+}
+
+// ResetPassword sets a new password and logs the user out everywhere.
+func ResetPassword(token, password string) error {
+	hashPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		userToken, err := consumeUserToken(tx, "password_reset", token)
+		if err != nil {
+			return err
+		}
+		if err := dbHelper.UpdateUserPassword(tx, userToken.UserID, hashPassword); err != nil {
+			return err
+		}
+		return dbHelper.RevokeUserSessions(tx, userToken.UserID, "password_reset")
+	})
+}
+
+func SendEmailVerification(userID string) error {
+	user, err := dbHelper.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	token, err := issueUserToken(user.ID, "email_verification", EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return mailer.Default().Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your storex email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address with the link below. It expires in %s.\n\n%s",
+			user.Name, EmailVerificationTTL, appLink("/verify-email", token)),
+	})
+}
+
+func VerifyEmail(token string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		userToken, err := consumeUserToken(tx, "email_verification", token)
+		if err != nil {
+			return err
+		}
+		return dbHelper.MarkEmailVerified(tx, userToken.UserID)
+	})
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+)
+
+// returnStatus maps the condition an asset is checked in with to the status it moves to.
+var returnStatus = map[string]string{
+	"good":         "available",
+	"fair":         "available",
+	"needs_repair": "for_repair",
+	"damaged":      "damaged",
+}
+
+// lockAsset locks the asset row for the rest of the transaction and returns its status.
+func lockAsset(tx *sqlx.Tx, assetID string) (string, error) {
+	status, err := dbHelper.GetAssetStatusForUpdate(tx, assetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrAssetNotFound
+	}
+	return status, err
+}
+
+func AssignAsset(assetID, assignedBy, assignedTo string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		return assignAsset(tx, assetID, assignedBy, assignedTo)
+	})
+}
+
+// assignAsset assigns an asset inside a transaction of the caller, for workflows that assign as
+// one of several steps.
+func assignAsset(tx *sqlx.Tx, assetID, assignedBy, assignedTo string) error {
+	from, err := lockAsset(tx, assetID)
+	if err != nil {
+		return err
+	}
+	if err := CheckTransition(from, "assigned"); err != nil {
+		return err
+	}
+	if from == "reserved" {
+		if err := convertReservation(tx, assetID, assignedBy, assignedTo); err != nil {
+			return err
+		}
+	}
+	if err := lockActiveUser(tx, assignedTo, false); err != nil {
+		return err
+	}
+	offboarding, err := dbHelper.IsOffboarding(tx, assignedTo)
+	if err != nil {
+		return err
+	}
+	if offboarding {
+		return ErrUserOffboarding
+	}
+	if err := moveAsset(tx, assetID, "", "assigned", assignedBy, ""); err != nil {
+		return err
+	}
+	if err := dbHelper.AssignedAssets(tx, assetID, assignedBy, assignedTo); err != nil {
+		return err
+	}
+	return dbHelper.CreateAssignment(tx, assetID, assignedTo, assignedBy)
+}
+
+// ReturnAsset takes an asset back into stock at the given location, or where it was kept before
+// it was assigned when none is given.
+func ReturnAsset(assetID, receivedBy, condition, notes, locationID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if from != "assigned" {
+			return ErrAssetNotAssigned
+		}
+		status := returnStatus[condition]
+		if err := CheckTransition(from, status); err != nil {
+			return err
+		}
+		if err := dbHelper.ReturnAsset(tx, assetID, receivedBy, condition, notes, status); err != nil {
+			return err
+		}
+		if err := dbHelper.CloseAssignment(tx, assetID, receivedBy, condition, notes); err != nil {
+			return err
+		}
+		if locationID == "" {
+			previous, err := dbHelper.LastLocationBeforeAssignment(tx, assetID)
+			if err != nil {
+				return err
+			}
+			if previous != nil {
+				locationID = *previous
+			}
+		}
+		if err := moveAsset(tx, assetID, locationID, "returned", receivedBy, notes); err != nil {
+			return err
+		}
+		return resolveReturnTask(tx, assetID, receivedBy, "returned", notes)
+	})
+}
+
+// ChangeAssetStatus moves an asset between statuses that do not involve an assignment.
+func ChangeAssetStatus(assetID, status string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if from == "assigned" || status == "assigned" {
+			return ErrAssignmentFlow
+		}
+		if from == "reserved" || status == "reserved" {
+			return ErrReservationFlow
+		}
+		if slices.Contains(missingAssetStatuses, from) || slices.Contains(missingAssetStatuses, status) {
+			return ErrIncidentFlow
+		}
+		if status == "disposed" {
+			return ErrDisposalFlow
+		}
+		ticketOpen, err := dbHelper.HasOpenServiceTicket(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if ticketOpen {
+			return ErrServiceTicketFlow
+		}
+		if err := CheckTransition(from, status); err != nil {
+			return err
+		}
+		return dbHelper.UpdateAssetStatus(tx, assetID, status)
+	})
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrAssetRequestNotFound = errors.New("asset request not found")
+	ErrAssetRequestClosed   = errors.New("asset request is no longer open")
+	ErrNotApprover          = errors.New("you are not the approver of the current step")
+	ErrNotApproved          = errors.New("asset request is not approved yet")
+	ErrAssetTypeMismatch    = errors.New("asset is not of the requested type")
+	ErrManagerLoop          = errors.New("user cannot report to themselves or to someone who reports to them")
+)
+
+// SetUserManager sets the manager who approves the asset requests of a user, an empty managerID
+// removes it. Requests already raised keep the manager they were raised with.
+func SetUserManager(userID, managerID string) error {
+	if _, err := getActiveUser(userID); err != nil {
+		return err
+	}
+	if managerID == "" {
+		return dbHelper.UpdateUserManager(userID, nil)
+	}
+	if _, err := getActiveUser(managerID); err != nil {
+		return fmt.Errorf("manager: %w", err)
+	}
+	loop, err := dbHelper.IsAboveInChain(managerID, userID)
+	if err != nil {
+		return err
+	}
+	if loop {
+		return ErrManagerLoop
+	}
+	return dbHelper.UpdateUserManager(userID, &managerID)
+}
+
+func ReplaceApprovalSteps(steps []models.ApprovalStepRequest) error {
+	for _, step := range steps {
+		if step.ApproverKind != "role" {
+			continue
+		}
+		if err := ensureRole(step.ApproverRole); err != nil {
+			return err
+		}
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		return dbHelper.ReplaceApprovalSteps(tx, steps)
+	})
+}
+
+// CreateAssetRequest raises a request with the approval chain configured at this time. A manager
+// step is skipped when the requester has no manager, and a request left with no step to decide is
+// approved straight away.
+func CreateAssetRequest(userID string, request models.CreateAssetRequestRequest) (string, error) {
+	if _, err := GetAssetType(request.AssetType); err != nil {
+		return "", err
+	}
+	var requestID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		var err error
+		requestID, err = dbHelper.CreateAssetRequest(tx, userID, request)
+		if err != nil {
+			return err
+		}
+		if err := dbHelper.CreateAssetRequestEvent(tx, requestID, userID, "created", "", "pending", ""); err != nil {
+			return err
+		}
+		managerID, err := dbHelper.GetUserManager(tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := dbHelper.SnapshotApprovalSteps(tx, requestID, managerID); err != nil {
+			return err
+		}
+		if managerID == nil {
+			skipped, err := dbHelper.SkipManagerSteps(tx, requestID, "requester has no manager")
+			if err != nil {
+				return err
+			}
+			if skipped > 0 {
+				if err := dbHelper.CreateAssetRequestEvent(tx, requestID, "", "manager_step_skipped", "pending", "pending", "requester has no manager"); err != nil {
+					return err
+				}
+			}
+		}
+		return approveIfComplete(tx, requestID)
+	})
+	return requestID, txErr
+}
+
+// approveIfComplete approves a pending request once no step is left to decide.
+func approveIfComplete(tx *sqlx.Tx, requestID string) error {
+	_, err := dbHelper.CurrentApproval(tx, requestID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err := dbHelper.UpdateAssetRequestStatus(tx, requestID, "approved", false); err != nil {
+		return err
+	}
+	return dbHelper.CreateAssetRequestEvent(tx, requestID, "", "approved", "pending", "approved", "all approval steps passed")
+}
+
+func lockAssetRequest(tx *sqlx.Tx, requestID string) (models.AssetRequest, error) {
+	request, err := dbHelper.GetAssetRequestForUpdate(tx, requestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return request, ErrAssetRequestNotFound
+	}
+	return request, err
+}
+
+// canDecide tells whether a user may decide an approval step. Nobody decides on their own request.
+func canDecide(approval models.AssetRequestApproval, request models.AssetRequest, user *models.UserCtx) bool {
+	if request.RequestedBy == user.UserID {
+		return false
+	}
+	switch approval.ApproverKind {
+	case "manager":
+		return approval.ApproverID != nil && *approval.ApproverID == user.UserID
+	case "role":
+		return approval.ApproverRole != nil && *approval.ApproverRole == user.Role
+	}
+	return false
+}
+
+// DecideAssetRequest approves or rejects the current step of a pending request. A rejection
+// closes the request, an approval of the last step approves it for fulfilment.
+func DecideAssetRequest(requestID string, user *models.UserCtx, decision, comment string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		request, err := lockAssetRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != "pending" {
+			return ErrAssetRequestClosed
+		}
+		approval, err := dbHelper.CurrentApproval(tx, requestID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAssetRequestClosed
+		}
+		if err != nil {
+			return err
+		}
+		if !canDecide(approval, request, user) {
+			return ErrNotApprover
+		}
+		if err := dbHelper.DecideApproval(tx, approval.ID, user.UserID, decision, comment); err != nil {
+			return err
+		}
+		if decision == "rejected" {
+			if err := dbHelper.UpdateAssetRequestStatus(tx, requestID, "rejected", true); err != nil {
+				return err
+			}
+			return dbHelper.CreateAssetRequestEvent(tx, requestID, user.UserID, "rejected", "pending", "rejected", comment)
+		}
+		event := fmt.Sprintf("step_%d_approved", approval.Position)
+		if err := dbHelper.CreateAssetRequestEvent(tx, requestID, user.UserID, event, "pending", "pending", comment); err != nil {
+			return err
+		}
+		return approveIfComplete(tx, requestID)
+	})
+}
+
+// CancelAssetRequest withdraws a request of the user that is not closed yet.
+func CancelAssetRequest(requestID, userID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		request, err := lockAssetRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.RequestedBy != userID {
+			return ErrAssetRequestNotFound
+		}
+		if request.Status != "pending" && request.Status != "approved" {
+			return ErrAssetRequestClosed
+		}
+		if err := dbHelper.UpdateAssetRequestStatus(tx, requestID, "cancelled", true); err != nil {
+			return err
+		}
+		return dbHelper.CreateAssetRequestEvent(tx, requestID, userID, "cancelled", request.Status, "cancelled", "")
+	})
+}
+
+// FulfilAssetRequest assigns an available asset of the requested type to the requester and closes
+// the request, in one transaction.
+func FulfilAssetRequest(requestID, fulfilledBy, assetID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		request, err := lockAssetRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		switch request.Status {
+		case "approved":
+		case "pending":
+			return ErrNotApproved
+		default:
+			return ErrAssetRequestClosed
+		}
+		if err := assignAsset(tx, assetID, fulfilledBy, request.RequestedBy); err != nil {
+			return err
+		}
+		assetType, err := dbHelper.GetAssetTypeName(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if assetType != request.AssetType {
+			return fmt.Errorf("%w: asset is a %s, request is for a %s", ErrAssetTypeMismatch, assetType, request.AssetType)
+		}
+		if err := dbHelper.FulfilAssetRequest(tx, requestID, assetID); err != nil {
+			return err
+		}
+		return dbHelper.CreateAssetRequestEvent(tx, requestID, fulfilledBy, "fulfilled", "approved", "fulfilled", "assigned asset "+assetID)
+	})
+}
+
+func GetAssetRequest(requestID string) (models.AssetRequestDetail, error) {
+	request, err := dbHelper.GetAssetRequest(requestID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.AssetRequestDetail{}, ErrAssetRequestNotFound
+	}
+	if err != nil {
+		return models.AssetRequestDetail{}, err
+	}
+	approvals, err := dbHelper.ListAssetRequestApprovals(requestID)
+	if err != nil {
+		return models.AssetRequestDetail{}, err
+	}
+	events, err := dbHelper.ListAssetRequestEvents(requestID)
+	if err != nil {
+		return models.AssetRequestDetail{}, err
+	}
+	return models.AssetRequestDetail{AssetRequest: request, Approvals: approvals, Events: events}, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrAssetNotFound    = errors.New("asset not found")
+	ErrAssetNotAssigned = errors.New("asset is not assigned")
+	ErrAssignmentFlow   = errors.New("assignment changes must go through the assign and return endpoints")
+)
+
+// assetTransitions lists, for every asset status, the statuses it is allowed to move to.
+var assetTransitions = map[string][]string{
+	"available":  {"assigned", "reserved", "for_repair", "damaged", "lost", "stolen", "disposed"},
+	"reserved":   {"assigned", "available", "lost", "stolen"},
+	"assigned":   {"available", "for_repair", "damaged", "lost", "stolen"},
+	"for_repair": {"in_service", "damaged", "lost", "stolen"},
+	"in_service": {"available", "damaged", "lost", "stolen"},
+	"damaged":    {"for_repair", "lost", "stolen", "disposed"},
+	"lost":       {"available", "for_repair", "damaged"},
+	"stolen":     {"available", "for_repair", "damaged"},
+}
+
+// initialStatuses are the statuses a new asset may be created with.
+var initialStatuses = []string{"available", "for_repair", "damaged"}
+
+type TransitionError struct {
+	// Subject is what is changing status; an empty subject means the asset itself.
+	Subject string
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	subject := e.Subject
+	if subject == "" {
+		subject = "asset"
+	}
+	if e.From == "" {
+		return fmt.Sprintf("%s cannot be created with status %s", subject, e.To)
+	}
+	return fmt.Sprintf("%s cannot move from %s to %s", subject, e.From, e.To)
+}
+
+func AllowedTransitions(from string) []string {
+	return assetTransitions[from]
+}
+
+func CheckTransition(from, to string) error {
+	allowed := AllowedTransitions(from)
+	if !slices.Contains(allowed, to) {
+		return &TransitionError{From: from, To: to, Allowed: allowed}
+	}
+	return nil
+}
+
+func CheckInitialStatus(status string) error {
+	if !slices.Contains(initialStatuses, status) {
+		return &TransitionError{To: status, Allowed: initialStatuses}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{name: "available to assigned", from: "available", to: "assigned"},
+		{name: "available to disposed", from: "available", to: "disposed"},
+		{name: "assigned to available", from: "assigned", to: "available"},
+		{name: "for repair to in service", from: "for_repair", to: "in_service"},
+		{name: "in service to available", from: "in_service", to: "available"},
+		{name: "damaged to disposed", from: "damaged", to: "disposed"},
+		{name: "lost to available", from: "lost", to: "available"},
+		{name: "stolen to for repair", from: "stolen", to: "for_repair"},
+		{name: "assigned to reserved", from: "assigned", to: "reserved", wantErr: true},
+		{name: "for repair to available", from: "for_repair", to: "available", wantErr: true},
+		{name: "assigned to disposed", from: "assigned", to: "disposed", wantErr: true},
+		{name: "disposed is final", from: "disposed", to: "available", wantErr: true},
+		{name: "same status", from: "available", to: "available", wantErr: true},
+		{name: "unknown status", from: "missing", to: "available", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTransition(tt.from, tt.to)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("CheckTransition(%q, %q) = %v, want nil", tt.from, tt.to, err)
+				}
+				return
+			}
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("CheckTransition(%q, %q) = %v, want *TransitionError", tt.from, tt.to, err)
+			}
+			if transitionErr.From != tt.from || transitionErr.To != tt.to {
+				t.Errorf("TransitionError = %s -> %s, want %s -> %s", transitionErr.From, transitionErr.To, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestCheckInitialStatus(t *testing.T) {
+	if err := CheckInitialStatus("available"); err != nil {
+		t.Errorf("CheckInitialStatus(available) = %v, want nil", err)
+	}
+	var transitionErr *TransitionError
+	if err := CheckInitialStatus("assigned"); !errors.As(err, &transitionErr) {
+		t.Errorf("CheckInitialStatus(assigned) = %v, want *TransitionError", err)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	ErrUnknownAssetType  = errors.New("unknown asset type")
+	ErrInvalidSpecSchema = errors.New("invalid spec schema")
+	ErrInvalidSpecs      = errors.New("specs do not match the asset type")
+)
+
+// ExistingDataError rejects a definition change that the data already stored does not satisfy.
+type ExistingDataError struct {
+	// Subject names the records checked, as in "assets".
+	Subject string
+	Reason  string
+	Invalid int
+}
+
+func (e *ExistingDataError) Error() string {
+	return fmt.Sprintf("%d %s %s", e.Invalid, e.Subject, e.Reason)
+}
+
+type compiledSpecSchema struct {
+	updatedAt time.Time
+	schema    *jsonschema.Schema
+}
+
+// specSchemas caches the compiled spec schema of every asset type until the type is updated.
+var specSchemas = struct {
+	sync.Mutex
+	byType map[string]compiledSpecSchema
+}{byType: make(map[string]compiledSpecSchema)}
+
+// CompileSpecSchema compiles the JSON Schema an asset type describes its specs with. The
+// schema must describe an object and may not reference other documents.
+func CompileSpecSchema(specSchema json.RawMessage) (*jsonschema.Schema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(specSchema, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecSchema, err)
+	}
+	if root["type"] != "object" {
+		return nil, fmt.Errorf("%w: top level type must be object", ErrInvalidSpecSchema)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("external reference %s is not allowed", url)
+	}
+	if err := compiler.AddResource("mem:///spec.json", bytes.NewReader(specSchema)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecSchema, err)
+	}
+	schema, err := compiler.Compile("mem:///spec.json")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecSchema, err)
+	}
+	return schema, nil
+}
+
+func specSchemaFor(assetType models.AssetType) (*jsonschema.Schema, error) {
+	specSchemas.Lock()
+	defer specSchemas.Unlock()
+
+	cached, ok := specSchemas.byType[assetType.Name]
+	if ok && cached.updatedAt.Equal(assetType.UpdatedAt) {
+		return cached.schema, nil
+	}
+	schema, err := CompileSpecSchema(assetType.SpecSchema)
+	if err != nil {
+		return nil, err
+	}
+	specSchemas.byType[assetType.Name] = compiledSpecSchema{updatedAt: assetType.UpdatedAt, schema: schema}
+	return schema, nil
+}
+
+func GetAssetType(name string) (models.AssetType, error) {
+	assetType, err := dbHelper.GetAssetType(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return assetType, ErrUnknownAssetType
+	}
+	return assetType, err
+}
+
+// ValidateSpecs checks the specs of an asset against the spec schema of its type. Missing
+// specs are returned as an empty object.
+func ValidateSpecs(assetTypeName string, specs json.RawMessage) (json.RawMessage, error) {
+	assetType, err := GetAssetType(assetTypeName)
+	if err != nil {
+		return nil, err
+	}
+	return validateSpecs(assetType, specs)
+}
+
+func validateSpecs(assetType models.AssetType, specs json.RawMessage) (json.RawMessage, error) {
+	schema, err := specSchemaFor(assetType)
+	if err != nil {
+		return nil, err
+	}
+	return checkSpecs(schema, specs)
+}
+
+func checkSpecs(schema *jsonschema.Schema, specs json.RawMessage) (json.RawMessage, error) {
+	if len(specs) == 0 || string(specs) == "null" {
+		specs = json.RawMessage("{}")
+	}
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(specs))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecs, err)
+	}
+	if err := schema.Validate(value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecs, err)
+	}
+	return specs, nil
+}
+
+// withoutRequiredSecrets drops the secret fields from the required properties of a spec schema.
+// Secrets are not kept in the stored specs, so they are checked only when they are written.
+func withoutRequiredSecrets(specSchema json.RawMessage) (json.RawMessage, error) {
+	fields := SecretFields(specSchema)
+	if len(fields) == 0 {
+		return specSchema, nil
+	}
+	var root map[string]any
+	if err := json.Unmarshal(specSchema, &root); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpecSchema, err)
+	}
+	required, _ := root["required"].([]any)
+	kept := make([]any, 0, len(required))
+	for _, field := range required {
+		if name, ok := field.(string); !ok || !slices.Contains(fields, name) {
+			kept = append(kept, field)
+		}
+	}
+	root["required"] = kept
+	return json.Marshal(root)
+}
+
+// UpdateAssetType changes an asset type. The specs of every active asset of the type must
+// still match the new spec schema, otherwise nothing is changed.
+func UpdateAssetType(name string, request models.UpdateAssetTypeRequest) error {
+	relaxed, err := withoutRequiredSecrets(request.SpecSchema)
+	if err != nil {
+		return err
+	}
+	schema, err := CompileSpecSchema(relaxed)
+	if err != nil {
+		return err
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		err := dbHelper.LockAssetType(tx, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUnknownAssetType
+		}
+		if err != nil {
+			return err
+		}
+		stored, err := dbHelper.ListAssetSpecsOfType(tx, name)
+		if err != nil {
+			return err
+		}
+		invalid := 0
+		for _, specs := range stored {
+			if _, err := checkSpecs(schema, specs); err != nil {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			return &ExistingDataError{Subject: "assets", Reason: "do not match the new spec schema", Invalid: invalid}
+		}
+		return dbHelper.UpdateAssetType(tx, name, request.Label, request.SpecSchema, request.TransferRequiresApproval)
+	})
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrAuditNotFound     = errors.New("audit campaign not found")
+	ErrAuditClosed       = errors.New("audit campaign is closed")
+	ErrInvalidAuditScope = errors.New("invalid audit scope")
+	ErrUnknownSerial     = errors.New("no asset with this serial number")
+	ErrUnexpectedMissing = errors.New("an asset outside the audit cannot be marked missing")
+)
+
+// assetOwners are the values of the owner_type enum.
+var assetOwners = []string{"client", "remotestate"}
+
+// OpenAudit opens a campaign and snapshots the assets expected in its scope.
+func OpenAudit(openedBy string, request models.OpenAuditRequest) (string, error) {
+	switch request.Scope {
+	case "type":
+		if _, err := GetAssetType(request.ScopeValue); err != nil {
+			return "", err
+		}
+	case "owner":
+		if !slices.Contains(assetOwners, request.ScopeValue) {
+			return "", fmt.Errorf("%w: owner must be one of %v", ErrInvalidAuditScope, assetOwners)
+		}
+	case "location":
+		if _, err := GetLocation(request.ScopeValue); err != nil {
+			return "", err
+		}
+	}
+
+	var campaignID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		var err error
+		campaignID, err = dbHelper.CreateAuditCampaign(tx, openedBy, request)
+		if err != nil {
+			return err
+		}
+		_, err = dbHelper.SnapshotAuditItems(tx, campaignID, request.Scope, request.ScopeValue)
+		return err
+	})
+	return campaignID, txErr
+}
+
+func lockAudit(tx *sqlx.Tx, campaignID string) error {
+	status, err := dbHelper.GetAuditStatusForUpdate(tx, campaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAuditNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != "open" {
+		return ErrAuditClosed
+	}
+	return nil
+}
+
+// RecordAuditResults records what the auditors saw for each serial number. An asset found that
+// was not in the snapshot is added to the campaign as unexpected.
+func RecordAuditResults(campaignID, checkedBy string, results []models.AuditResultRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := lockAudit(tx, campaignID); err != nil {
+			return err
+		}
+		for _, result := range results {
+			assetID, err := dbHelper.GetAssetIDBySerial(tx, result.SerialNumber)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrUnknownSerial, result.SerialNumber)
+			}
+			if err != nil {
+				return err
+			}
+			if result.Result == "missing" {
+				expected, err := dbHelper.IsExpectedInAudit(tx, campaignID, assetID)
+				if err != nil {
+					return err
+				}
+				if !expected {
+					return fmt.Errorf("%w: %s", ErrUnexpectedMissing, result.SerialNumber)
+				}
+			}
+			if err := dbHelper.RecordAuditResult(tx, campaignID, assetID, checkedBy, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CloseAudit closes a campaign and returns its discrepancy report. When asked, every asset still
+// marked missing is reported lost; assets that can no longer become lost, for instance because
+// they were disposed of meanwhile, are left as they are.
+func CloseAudit(campaignID, closedBy string, request models.CloseAuditRequest) (models.AuditReport, error) {
+	campaign, err := GetAuditCampaign(campaignID)
+	if err != nil {
+		return models.AuditReport{}, err
+	}
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		if err := lockAudit(tx, campaignID); err != nil {
+			return err
+		}
+		if request.FlagMissingAsLost {
+			assetIDs, err := dbHelper.ListMissingAuditAssets(tx, campaignID)
+			if err != nil {
+				return err
+			}
+			for _, assetID := range assetIDs {
+				from, err := lockAsset(tx, assetID)
+				if errors.Is(err, ErrAssetNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if CheckTransition(from, "lost") != nil {
+					continue
+				}
+				_, err = recordIncident(tx, assetID, from, closedBy, models.ReportIncidentRequest{
+					Kind:       "lost",
+					OccurredOn: time.Now(),
+					Remarks:    "missing in audit " + campaign.Name,
+				})
+				if err != nil {
+					return err
+				}
+				if err := dbHelper.MarkAuditItemFlagged(tx, campaignID, assetID); err != nil {
+					return err
+				}
+			}
+		}
+		return dbHelper.CloseAuditCampaign(tx, campaignID, closedBy, request.FlagMissingAsLost)
+	})
+	if txErr != nil {
+		return models.AuditReport{}, txErr
+	}
+	return AuditReport(campaignID)
+}
+
+func GetAuditCampaign(campaignID string) (models.AuditCampaign, error) {
+	campaign, err := dbHelper.GetAuditCampaign(campaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return campaign, ErrAuditNotFound
+	}
+	return campaign, err
+}
+
+func AuditReport(campaignID string) (models.AuditReport, error) {
+	campaign, err := GetAuditCampaign(campaignID)
+	if err != nil {
+		return models.AuditReport{}, err
+	}
+	discrepancies, err := dbHelper.ListAuditDiscrepancies(campaignID)
+	if err != nil {
+		return models.AuditReport{}, err
+	}
+	return models.AuditReport{
+		Campaign:      campaign,
+		Discrepancies: discrepancies,
+	}, nil
+}
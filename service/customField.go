@@ -0,0 +1,171 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrInvalidCustomFields = errors.New("invalid custom fields")
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+)
+
+// customFieldFilterPrefix marks the query parameters that filter on a custom field, as in cf.costCenter=CC-12.
+const customFieldFilterPrefix = "cf."
+
+func customFieldDefinitions(entity string) (map[string]models.CustomFieldDefinition, error) {
+	definitions, err := dbHelper.ListCustomFields(entity)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]models.CustomFieldDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.Key] = definition
+	}
+	return byKey, nil
+}
+
+func checkCustomFieldValue(definition models.CustomFieldDefinition, value any) error {
+	var ok bool
+	switch definition.FieldType {
+	case "number":
+		_, ok = value.(float64)
+	case "boolean":
+		_, ok = value.(bool)
+	case "date":
+		var date string
+		if date, ok = value.(string); ok {
+			_, err := time.Parse(time.DateOnly, date)
+			ok = err == nil
+		}
+	case "select":
+		var choice string
+		if choice, ok = value.(string); ok {
+			ok = slices.Contains(definition.AllowedValues, choice)
+		}
+	default:
+		_, ok = value.(string)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s must be a valid %s", ErrInvalidCustomFields, definition.Key, definition.FieldType)
+	}
+	return nil
+}
+
+// ValidateCustomFields checks custom field values against the definitions of the entity,
+// rejecting unknown fields, and returns them ready to be stored.
+func ValidateCustomFields(entity string, customFields json.RawMessage) (json.RawMessage, error) {
+	values := make(map[string]any)
+	if len(customFields) > 0 && string(customFields) != "null" {
+		if err := json.Unmarshal(customFields, &values); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCustomFields, err)
+		}
+	}
+	definitions, err := customFieldDefinitions(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range values {
+		if _, ok := definitions[key]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidCustomFields, key)
+		}
+	}
+	for key, definition := range definitions {
+		value, ok := values[key]
+		if !ok || value == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("%w: %s is required", ErrInvalidCustomFields, key)
+			}
+			delete(values, key)
+			continue
+		}
+		if err := checkCustomFieldValue(definition, value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(values)
+}
+
+// UpdateCustomField changes a custom field definition. Making the field required or narrowing
+// the allowed values of a select field is refused while stored records would not satisfy it.
+func UpdateCustomField(fieldID string, request models.UpdateCustomFieldDefinitionRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		definition, err := dbHelper.GetCustomFieldForUpdate(tx, fieldID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCustomFieldNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var allowedValues []string
+		if definition.FieldType == "select" {
+			allowedValues = request.AllowedValues
+			if allowedValues == nil {
+				allowedValues = make([]string, 0)
+			}
+		}
+		invalid, err := dbHelper.CountCustomFieldViolations(tx, definition, request.Required, allowedValues)
+		if err != nil {
+			return err
+		}
+		if invalid > 0 {
+			return &ExistingDataError{Subject: definition.Entity + " records", Reason: "do not satisfy the custom field change", Invalid: invalid}
+		}
+		return dbHelper.UpdateCustomField(tx, fieldID, request)
+	})
+}
+
+// CustomFieldFilter turns the cf.<key>=value query parameters into a JSON object the stored
+// custom fields of the entity have to contain.
+func CustomFieldFilter(entity string, query url.Values) (json.RawMessage, error) {
+	filter := make(map[string]any)
+	var definitions map[string]models.CustomFieldDefinition
+	for param := range query {
+		key, ok := strings.CutPrefix(param, customFieldFilterPrefix)
+		if !ok {
+			continue
+		}
+		if definitions == nil {
+			var err error
+			if definitions, err = customFieldDefinitions(entity); err != nil {
+				return nil, err
+			}
+		}
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidCustomFields, key)
+		}
+
+		raw := query.Get(param)
+		switch definition.FieldType {
+		case "number":
+			number, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidCustomFields, key)
+			}
+			filter[key] = number
+		case "boolean":
+			flag, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidCustomFields, key)
+			}
+			filter[key] = flag
+		default:
+			filter[key] = raw
+		}
+	}
+	return json.Marshal(filter)
+}
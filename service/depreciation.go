@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var ErrAssetNotValued = errors.New("asset has no purchase price, purchase date or depreciation policy")
+
+// elapsedMonths counts the whole months between two dates.
+func elapsedMonths(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return max(months, 0)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Depreciate returns the book value on asOf of an asset bought for cost on purchaseDate.
+// Straight line writes the cost above the salvage value off evenly over the useful life.
+// Declining balance applies the yearly rate (twice the straight line rate unless the policy
+// sets one) to the remaining value month by month, never going below the salvage value,
+// and reaches the salvage value at the end of the useful life.
+func Depreciate(cost float64, purchaseDate, asOf time.Time, policy models.DepreciationPolicy) float64 {
+	salvage := cost * policy.SalvagePercent / 100
+	months := min(elapsedMonths(purchaseDate, asOf), policy.UsefulLifeMonths)
+	if months == policy.UsefulLifeMonths {
+		return roundCents(salvage)
+	}
+
+	switch policy.Method {
+	case "declining_balance":
+		rate := 2 / (float64(policy.UsefulLifeMonths) / 12)
+		if policy.DecliningRate != nil {
+			rate = *policy.DecliningRate / 100
+		}
+		rate = min(rate, 1)
+		value := cost * math.Pow(1-rate, float64(months)/12)
+		return roundCents(max(value, salvage))
+	default:
+		depreciated := (cost - salvage) * float64(months) / float64(policy.UsefulLifeMonths)
+		return roundCents(cost - depreciated)
+	}
+}
+
+func bookValue(asset models.ValuedAsset, asOf time.Time) (models.BookValue, error) {
+	if asset.PurchasePrice == nil || asset.PurchaseDate == nil || asset.Currency == nil || asset.Method == nil {
+		return models.BookValue{}, ErrAssetNotValued
+	}
+	policy := models.DepreciationPolicy{
+		AssetType:        asset.AssetType,
+		Method:           *asset.Method,
+		UsefulLifeMonths: *asset.UsefulLifeMonths,
+		SalvagePercent:   *asset.SalvagePercent,
+		DecliningRate:    asset.DecliningRate,
+	}
+	value := Depreciate(*asset.PurchasePrice, *asset.PurchaseDate, asOf, policy)
+	return models.BookValue{
+		AssetID:                 asset.ID,
+		SerialNumber:            asset.SerialNumber,
+		AssetType:               asset.AssetType,
+		PurchaseDate:            *asset.PurchaseDate,
+		PurchasePrice:           *asset.PurchasePrice,
+		Currency:                *asset.Currency,
+		Method:                  policy.Method,
+		UsefulLifeMonths:        policy.UsefulLifeMonths,
+		AccumulatedDepreciation: roundCents(*asset.PurchasePrice - value),
+		BookValue:               value,
+		AsOf:                    asOf,
+	}, nil
+}
+
+func AssetBookValue(assetID string, asOf time.Time) (models.BookValue, error) {
+	asset, err := dbHelper.GetValuedAsset(assetID)
+	if err != nil {
+		return models.BookValue{}, err
+	}
+	return bookValue(asset, asOf)
+}
+
+// DepreciationReport values every asset held on asOf, totalled per currency. Assets that
+// cannot be valued are listed by serial number.
+func DepreciationReport(asOf time.Time) (models.DepreciationReport, error) {
+	report := models.DepreciationReport{
+		AsOf:     asOf,
+		Totals:   make([]models.DepreciationTotal, 0),
+		Assets:   make([]models.BookValue, 0),
+		Unvalued: make([]string, 0),
+	}
+	assets, err := dbHelper.ListValuedAssets(asOf)
+	if err != nil {
+		return report, err
+	}
+
+	totals := make(map[string]*models.DepreciationTotal)
+	for _, asset := range assets {
+		value, err := bookValue(asset, asOf)
+		if err != nil {
+			report.Unvalued = append(report.Unvalued, asset.SerialNumber)
+			continue
+		}
+		report.Assets = append(report.Assets, value)
+
+		total, ok := totals[value.Currency]
+		if !ok {
+			total = &models.DepreciationTotal{Currency: value.Currency}
+			totals[value.Currency] = total
+		}
+		total.Assets++
+		total.Cost = roundCents(total.Cost + value.PurchasePrice)
+		total.AccumulatedDepreciation = roundCents(total.AccumulatedDepreciation + value.AccumulatedDepreciation)
+		total.BookValue = roundCents(total.BookValue + value.BookValue)
+	}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+	return report, nil
+}
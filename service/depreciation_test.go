@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDepreciate(t *testing.T) {
+	straightLine := models.DepreciationPolicy{Method: "straight_line", UsefulLifeMonths: 36, SalvagePercent: 10}
+	declining := models.DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 60}
+	decliningSalvage := models.DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 60, SalvagePercent: 5}
+	decliningFloor := models.DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 60, SalvagePercent: 40}
+	rate := 25.0
+	decliningRate := models.DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 60, DecliningRate: &rate}
+	purchased := date(2024, time.January, 15)
+
+	tests := []struct {
+		name   string
+		cost   float64
+		asOf   time.Time
+		policy models.DepreciationPolicy
+		want   float64
+	}{
+		{name: "straight line on purchase date", cost: 1200, asOf: purchased, policy: straightLine, want: 1200},
+		{name: "straight line after a year", cost: 1200, asOf: date(2025, time.January, 15), policy: straightLine, want: 840},
+		{name: "straight line day before a whole month", cost: 1200, asOf: date(2024, time.February, 14), policy: straightLine, want: 1200},
+		{name: "straight line on a whole month", cost: 1200, asOf: date(2024, time.February, 15), policy: straightLine, want: 1170},
+		{name: "straight line at end of life", cost: 1200, asOf: date(2027, time.January, 15), policy: straightLine, want: 120},
+		{name: "straight line after end of life", cost: 1200, asOf: date(2030, time.June, 1), policy: straightLine, want: 120},
+		{name: "as of before purchase", cost: 1200, asOf: date(2023, time.December, 31), policy: straightLine, want: 1200},
+		{name: "declining balance after a year", cost: 1000, asOf: date(2025, time.January, 15), policy: declining, want: 600},
+		{name: "declining balance after two years", cost: 1000, asOf: date(2026, time.January, 15), policy: declining, want: 360},
+		{name: "declining balance part year", cost: 1000, asOf: date(2024, time.July, 15), policy: declining, want: 774.6},
+		{name: "declining balance with policy rate", cost: 1000, asOf: date(2025, time.January, 15), policy: decliningRate, want: 750},
+		{name: "declining balance floors at salvage", cost: 1000, asOf: date(2026, time.January, 15), policy: decliningFloor, want: 400},
+		{name: "declining balance final month", cost: 1000, asOf: date(2028, time.December, 15), policy: decliningSalvage, want: 81.14},
+		{name: "declining balance jumps to salvage at end of life", cost: 1000, asOf: date(2029, time.January, 15), policy: decliningSalvage, want: 50},
+		{name: "declining balance without salvage ends at zero", cost: 1000, asOf: date(2029, time.January, 15), policy: declining, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Depreciate(tt.cost, purchased, tt.asOf, tt.policy)
+			if got != tt.want {
+				t.Errorf("Depreciate(%v, %s, %s) = %v, want %v", tt.cost, purchased.Format(time.DateOnly), tt.asOf.Format(time.DateOnly), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElapsedMonths(t *testing.T) {
+	tests := []struct {
+		from, to time.Time
+		want     int
+	}{
+		{from: date(2024, time.January, 31), to: date(2024, time.February, 29), want: 0},
+		{from: date(2024, time.January, 31), to: date(2024, time.March, 31), want: 2},
+		{from: date(2024, time.December, 1), to: date(2025, time.January, 1), want: 1},
+		{from: date(2024, time.June, 1), to: date(2024, time.May, 1), want: 0},
+	}
+	for _, tt := range tests {
+		if got := elapsedMonths(tt.from, tt.to); got != tt.want {
+			t.Errorf("elapsedMonths(%s, %s) = %d, want %d", tt.from.Format(time.DateOnly), tt.to.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrDisposalNotFound    = errors.New("disposal not found")
+	ErrDisposalClosed      = errors.New("disposal is not waiting for this step")
+	ErrDisposalPending     = errors.New("asset already has an open disposal")
+	ErrDisposalFlow        = errors.New("disposals must go through the disposal endpoints")
+	ErrDataNotWiped        = errors.New("data wipe must be confirmed before the asset leaves")
+	ErrSelfApproval        = errors.New("cannot decide your own request")
+	ErrCertificateNotFound = errors.New("disposal has no certificate")
+	ErrCertificateType     = errors.New("certificate must be a PDF, PNG or JPEG file")
+)
+
+// MaxCertificateSize caps the size of an uploaded disposal certificate.
+const MaxCertificateSize = 10 << 20
+
+// CertificateContentTypes are the file types a disposal certificate may be stored and served as.
+var CertificateContentTypes = []string{"application/pdf", "image/png", "image/jpeg"}
+
+// RequestDisposal asks to take an asset out of the company. The asset stays in inventory until
+// the disposal is approved and completed.
+func RequestDisposal(assetID, requestedBy string, request models.RequestDisposalRequest) (string, error) {
+	var disposalID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if err := CheckTransition(from, "disposed"); err != nil {
+			return err
+		}
+		open, err := dbHelper.HasOpenDisposal(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrDisposalPending
+		}
+		disposalID, err = dbHelper.CreateDisposal(tx, assetID, requestedBy, request)
+		return err
+	})
+	return disposalID, txErr
+}
+
+func lockDisposal(tx *sqlx.Tx, disposalID string) (models.AssetDisposal, error) {
+	disposal, err := dbHelper.GetDisposalForUpdate(tx, disposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return disposal, ErrDisposalNotFound
+	}
+	return disposal, err
+}
+
+// DecideDisposal approves or rejects a disposal. Nobody decides on their own request.
+func DecideDisposal(disposalID, decidedBy string, approve bool, notes string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		disposal, err := lockDisposal(tx, disposalID)
+		if err != nil {
+			return err
+		}
+		if disposal.Status != "pending_approval" {
+			return ErrDisposalClosed
+		}
+		if disposal.RequestedBy == decidedBy {
+			return ErrSelfApproval
+		}
+		status := "rejected"
+		if approve {
+			status = "approved"
+		}
+		return dbHelper.DecideDisposal(tx, disposalID, status, decidedBy, notes)
+	})
+}
+
+func CancelDisposal(disposalID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		disposal, err := lockDisposal(tx, disposalID)
+		if err != nil {
+			return err
+		}
+		if disposal.Status != "pending_approval" && disposal.Status != "approved" {
+			return ErrDisposalClosed
+		}
+		return dbHelper.CancelDisposal(tx, disposalID)
+	})
+}
+
+// CompleteDisposal records that an approved disposal took place and moves the asset to disposed.
+// The book value on the disposal date is written off against the proceeds; when the asset
+// cannot be valued, or was sold in another currency, no gain or loss is recorded.
+func CompleteDisposal(disposalID, completedBy string, request models.CompleteDisposalRequest) error {
+	if !request.DataWiped {
+		return ErrDataNotWiped
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		disposal, err := lockDisposal(tx, disposalID)
+		if err != nil {
+			return err
+		}
+		if disposal.Status != "approved" {
+			return ErrDisposalClosed
+		}
+		from, err := lockAsset(tx, disposal.AssetID)
+		if err != nil {
+			return err
+		}
+		if err := CheckTransition(from, "disposed"); err != nil {
+			return err
+		}
+
+		asset, err := dbHelper.GetValuedAsset(disposal.AssetID)
+		if err != nil {
+			return err
+		}
+		currency := asset.Currency
+		if request.Currency != "" {
+			currency = &request.Currency
+		}
+		var bookValuePtr, gainLoss *float64
+		value, err := bookValue(asset, request.DisposedOn)
+		if err == nil {
+			bookValuePtr = &value.BookValue
+			if currency != nil && *currency == value.Currency {
+				proceeds := 0.0
+				if request.Proceeds != nil {
+					proceeds = *request.Proceeds
+				}
+				difference := roundCents(proceeds - value.BookValue)
+				gainLoss = &difference
+			}
+		} else if !errors.Is(err, ErrAssetNotValued) {
+			return err
+		}
+
+		if err := dbHelper.CompleteDisposal(tx, disposalID, completedBy, request, currency, bookValuePtr, gainLoss); err != nil {
+			return err
+		}
+		if err := moveAsset(tx, disposal.AssetID, "", "disposed", completedBy, request.Recipient); err != nil {
+			return err
+		}
+		return dbHelper.UpdateAssetStatus(tx, disposal.AssetID, "disposed")
+	})
+}
+
+func GetDisposal(disposalID string) (models.AssetDisposal, error) {
+	disposal, err := dbHelper.GetDisposal(disposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return disposal, ErrDisposalNotFound
+	}
+	return disposal, err
+}
+
+// SaveDisposalCertificate attaches the certificate of an approved or completed disposal.
+// contentType is the type sniffed from the content, never the one claimed by the uploader.
+func SaveDisposalCertificate(disposalID, name, contentType string, content []byte) error {
+	if !slices.Contains(CertificateContentTypes, contentType) {
+		return ErrCertificateType
+	}
+	if _, err := GetDisposal(disposalID); err != nil {
+		return err
+	}
+	saved, err := dbHelper.SaveDisposalCertificate(disposalID, name, contentType, content)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrDisposalClosed
+	}
+	return nil
+}
+
+func GetDisposalCertificate(disposalID string) (models.DisposalCertificate, error) {
+	certificate, err := dbHelper.GetDisposalCertificate(disposalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return certificate, ErrCertificateNotFound
+	}
+	return certificate, err
+}
+
+// DisposalReport lists the disposals completed between from and to and totals, per currency,
+// the cost and book value written off against the proceeds.
+func DisposalReport(from, to *time.Time) (models.DisposalReport, error) {
+	report := models.DisposalReport{
+		From:      from,
+		To:        to,
+		Totals:    make([]models.DisposalTotal, 0),
+		Disposals: make([]models.DisposedAsset, 0),
+	}
+	disposals, err := dbHelper.ListDisposedAssets(from, to)
+	if err != nil {
+		return report, err
+	}
+	report.Disposals = disposals
+
+	totals := make(map[string]*models.DisposalTotal)
+	for _, disposal := range disposals {
+		if disposal.GainLoss == nil || disposal.Currency == nil {
+			continue
+		}
+		total, ok := totals[*disposal.Currency]
+		if !ok {
+			total = &models.DisposalTotal{Currency: *disposal.Currency}
+			totals[*disposal.Currency] = total
+		}
+		total.Assets++
+		if disposal.PurchasePrice != nil {
+			total.Cost = roundCents(total.Cost + *disposal.PurchasePrice)
+		}
+		total.BookValue = roundCents(total.BookValue + *disposal.BookValue)
+		if disposal.Proceeds != nil {
+			total.Proceeds = roundCents(total.Proceeds + *disposal.Proceeds)
+		}
+		total.GainLoss = roundCents(total.GainLoss + *disposal.GainLoss)
+	}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+	return report, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/mailer"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrAssetNotMissing   = errors.New("asset is not lost or stolen")
+	ErrIncidentFlow      = errors.New("lost and stolen assets must go through the incident endpoints")
+	ErrReminderNotFound  = errors.New("pending rotation reminder not found")
+	missingAssetStatuses = []string{"lost", "stolen"}
+)
+
+// RotationReminderInterval is how often asset managers are reminded of a password still to rotate.
+const RotationReminderInterval = 24 * time.Hour
+
+func ReportIncident(assetID, reportedBy string, request models.ReportIncidentRequest) (string, error) {
+	var incidentID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if err := CheckTransition(from, request.Kind); err != nil {
+			return err
+		}
+		incidentID, err = recordIncident(tx, assetID, from, reportedBy, request)
+		return err
+	})
+	return incidentID, txErr
+}
+
+// recordIncident takes a locked asset out of circulation as lost or stolen: its holder, if any,
+// loses it, its reservation, pending transfers and open service ticket are closed, and every
+// stored device password gets a rotation reminder.
+func recordIncident(tx *sqlx.Tx, assetID, from, reportedBy string, request models.ReportIncidentRequest) (string, error) {
+	notes := "reported " + request.Kind
+	if request.Remarks != "" {
+		notes += ": " + request.Remarks
+	}
+	switch from {
+	case "assigned":
+		if err := dbHelper.CloseAssignment(tx, assetID, "", "", notes); err != nil {
+			return "", err
+		}
+		if err := resolveReturnTask(tx, assetID, reportedBy, "written_off", notes); err != nil {
+			return "", err
+		}
+	case "reserved":
+		reservation, err := dbHelper.GetActiveReservationForUpdate(tx, assetID)
+		if err != nil {
+			return "", err
+		}
+		if err := dbHelper.CloseReservation(tx, reservation.ID, "cancelled", reportedBy); err != nil {
+			return "", err
+		}
+	}
+	if err := dbHelper.CancelOpenTransfers(tx, assetID); err != nil {
+		return "", err
+	}
+	if err := dbHelper.CloseOpenServiceTickets(tx, assetID, reportedBy, request.Kind, notes); err != nil {
+		return "", err
+	}
+	if err := moveAsset(tx, assetID, "", "lost", reportedBy, notes); err != nil {
+		return "", err
+	}
+	if err := dbHelper.WriteOffAsset(tx, assetID, request.Kind); err != nil {
+		return "", err
+	}
+	incidentID, err := dbHelper.CreateIncident(tx, assetID, reportedBy, request)
+	if err != nil {
+		return "", err
+	}
+	return incidentID, dbHelper.CreateRotationReminders(tx, assetID, incidentID)
+}
+
+// RecoverAsset moves a found asset back into inventory, with the status its condition calls for,
+// at the location given if any. The incident is kept as recovered.
+func RecoverAsset(assetID, recoveredBy string, request models.RecoverAssetRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if from != "lost" && from != "stolen" {
+			return ErrAssetNotMissing
+		}
+		status := returnStatus[request.Condition]
+		if err := CheckTransition(from, status); err != nil {
+			return err
+		}
+		incident, err := dbHelper.GetOpenIncidentForUpdate(tx, assetID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAssetNotMissing
+		}
+		if err != nil {
+			return err
+		}
+		if err := dbHelper.RecoverIncident(tx, incident.ID, recoveredBy, request.Condition, request.Notes); err != nil {
+			return err
+		}
+		if err := moveAsset(tx, assetID, request.LocationID, "recovered", recoveredBy, request.Notes); err != nil {
+			return err
+		}
+		return dbHelper.UpdateAssetStatus(tx, assetID, status)
+	})
+}
+
+func CompleteRotationReminder(reminderID, completedBy string) error {
+	completed, err := dbHelper.CompleteRotationReminder(reminderID, completedBy)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return ErrReminderNotFound
+	}
+	return nil
+}
+
+// SendRotationReminders mails the asset managers the device passwords of missing assets still
+// waiting to be rotated, at most once per RotationReminderInterval for each.
+func SendRotationReminders(now time.Time) error {
+	reminders, err := dbHelper.ListDueRotationReminders(now.Add(-RotationReminderInterval))
+	if err != nil || len(reminders) == 0 {
+		return err
+	}
+	recipients, err := dbHelper.ListEmailsWithPermission("incident.manage")
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+
+	var body strings.Builder
+	body.WriteString("The following secrets belong to assets reported lost or stolen and must be changed on every system they give access to. Mark a reminder done once rotated, or store the new value on the asset.\n\n")
+	reminderIDs := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		fmt.Fprintf(&body, "- %s of asset %s (%s since %s)\n", reminder.Field, reminder.SerialNumber,
+			reminder.IncidentKind, reminder.CreatedAt.Format(time.DateOnly))
+		reminderIDs = append(reminderIDs, reminder.ID)
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		err := mailer.Default().Send(mailer.Message{
+			To:      recipient,
+			Subject: fmt.Sprintf("%d device passwords to rotate", len(reminders)),
+			Body:    body.String(),
+		})
+		if err != nil {
+			fmt.Printf("failed to send rotation reminder to %s: %v\n", recipient, err)
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return errors.New("no rotation reminder could be sent")
+	}
+	return dbHelper.MarkRemindersSent(reminderIDs)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrKitTemplateNotFound = errors.New("kit template not found")
+	ErrKitTemplateExists   = errors.New("kit template name is taken")
+	ErrInvalidKit          = errors.New("invalid kit")
+	ErrNoKitTemplate       = errors.New("no kit template matches the role and employment type of the user")
+)
+
+// KitShortageError is returned when a kit cannot be assigned because some asset types are out of stock.
+type KitShortageError struct {
+	Shortages []models.KitShortage
+}
+
+func (e *KitShortageError) Error() string {
+	return "not enough available assets to assign the kit"
+}
+
+func checkKitTemplate(templateID string, request models.KitTemplateRequest) error {
+	taken, err := dbHelper.IsKitTemplateNameTaken(request.Name, templateID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrKitTemplateExists
+	}
+	if request.Role != "" {
+		if err := ensureRole(request.Role); err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool)
+	for _, item := range request.Items {
+		if seen[item.AssetType] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidKit, item.AssetType)
+		}
+		seen[item.AssetType] = true
+		if _, err := GetAssetType(item.AssetType); err != nil {
+			return fmt.Errorf("%w: %s", err, item.AssetType)
+		}
+	}
+	return nil
+}
+
+func CreateKitTemplate(request models.KitTemplateRequest) (string, error) {
+	if err := checkKitTemplate("", request); err != nil {
+		return "", err
+	}
+	var templateID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		var err error
+		templateID, err = dbHelper.CreateKitTemplate(tx, request)
+		if err != nil {
+			return err
+		}
+		return dbHelper.ReplaceKitItems(tx, templateID, request.Items)
+	})
+	return templateID, txErr
+}
+
+func UpdateKitTemplate(templateID string, request models.KitTemplateRequest) error {
+	if err := checkKitTemplate(templateID, request); err != nil {
+		return err
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		updated, err := dbHelper.UpdateKitTemplate(tx, templateID, request)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrKitTemplateNotFound
+		}
+		return dbHelper.ReplaceKitItems(tx, templateID, request.Items)
+	})
+}
+
+func DeleteKitTemplate(templateID string) error {
+	archived, err := dbHelper.ArchiveKitTemplate(templateID)
+	if err != nil {
+		return err
+	}
+	if !archived {
+		return ErrKitTemplateNotFound
+	}
+	return nil
+}
+
+func GetKitTemplate(templateID string) (models.KitTemplate, error) {
+	template, err := dbHelper.GetKitTemplate(templateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return template, ErrKitTemplateNotFound
+	}
+	return template, err
+}
+
+// AssignKit assigns a whole kit to a user in one transaction: either every asset of the kit is
+// assigned or none is, and a shortage lists what is missing. An empty templateID picks the
+// template matching the user.
+func AssignKit(userID, templateID, assignedBy string) ([]models.KitAsset, error) {
+	user, err := getActiveUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if templateID == "" {
+		templateID, err = dbHelper.MatchKitTemplate(user.Role, user.Employment)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoKitTemplate
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := GetKitTemplate(templateID); err != nil {
+		return nil, err
+	}
+	items, err := dbHelper.ListKitItems(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make([]models.KitAsset, 0)
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		shortages := make([]models.KitShortage, 0)
+		for _, item := range items {
+			assets, err := dbHelper.PickAvailableAssets(tx, item.AssetType, item.Quantity)
+			if err != nil {
+				return err
+			}
+			if len(assets) < item.Quantity {
+				shortages = append(shortages, models.KitShortage{
+					AssetType: item.AssetType,
+					Required:  item.Quantity,
+					Available: len(assets),
+				})
+				continue
+			}
+			assigned = append(assigned, assets...)
+		}
+		if len(shortages) > 0 {
+			return &KitShortageError{Shortages: shortages}
+		}
+		for _, asset := range assigned {
+			if err := assignAsset(tx, asset.ID, assignedBy, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return assigned, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/labels"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrUnknownCode   = errors.New("no asset matches the scanned code")
+	ErrTooManyLabels = errors.New("too many labels for one sheet download")
+	ErrNoLabels      = errors.New("no asset matches the label filter")
+)
+
+// MaxLabelsPerSheet caps the labels rendered in a single PDF download.
+const MaxLabelsPerSheet = 1000
+
+func toLabel(asset models.AssetLabel) labels.Label {
+	return labels.Label{
+		AssetTag:     asset.AssetTag,
+		SerialNumber: asset.SerialNumber,
+		Brand:        asset.Brand,
+		Model:        asset.Model,
+	}
+}
+
+// AssetLabelPNG renders the label of an asset: the full label, or only its qr or code128 part.
+func AssetLabelPNG(assetID, kind string) ([]byte, error) {
+	asset, err := dbHelper.GetAssetLabel(assetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAssetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "qr":
+		return labels.QRCodePNG(toLabel(asset))
+	case "code128":
+		return labels.Code128PNG(toLabel(asset))
+	default:
+		return labels.LabelPNG(toLabel(asset))
+	}
+}
+
+// LabelSheetPDF renders the labels of the assets matching the filter on A4 sticker sheets.
+func LabelSheetPDF(filter models.LabelFilter) ([]byte, error) {
+	assets, err := dbHelper.ListAssetLabels(filter, MaxLabelsPerSheet+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, ErrNoLabels
+	}
+	if len(assets) > MaxLabelsPerSheet {
+		return nil, fmt.Errorf("%w: narrow the filter to at most %d assets", ErrTooManyLabels, MaxLabelsPerSheet)
+	}
+	sheet := make([]labels.Label, 0, len(assets))
+	for _, asset := range assets {
+		sheet = append(sheet, toLabel(asset))
+	}
+	return labels.SheetPDF(sheet)
+}
+
+// ScanAsset resolves a scanned asset tag or serial number to the asset, with its location.
+func ScanAsset(code string) (models.AssetDetail, error) {
+	asset, err := dbHelper.GetAssetByCode(strings.TrimSpace(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return asset, ErrUnknownCode
+	}
+	if err != nil {
+		return asset, err
+	}
+	if asset.Specs, err = make(secretRedactor).redact(asset.AssetType, asset.Specs); err != nil {
+		return asset, err
+	}
+	if asset.LocationID != nil {
+		location, err := dbHelper.GetLocation(*asset.LocationID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return asset, err
+		}
+		if err == nil {
+			asset.Location = &location
+		}
+	}
+	return asset, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrLocationNotFound      = errors.New("location not found")
+	ErrInvalidLocationParent = errors.New("invalid parent location")
+	ErrLocationExists        = errors.New("location name is taken")
+	ErrLocationInUse         = errors.New("location still holds assets or other locations")
+	ErrAssetNotMovable       = errors.New("only assets in stock can be moved between locations")
+)
+
+// locationParents lists, for every kind of location below a site, the kinds it may sit in.
+var locationParents = map[string][]string{
+	"floor":   {"site"},
+	"room":    {"floor"},
+	"cabinet": {"floor", "room"},
+}
+
+// stockStatuses are the statuses of assets kept at a location.
+var stockStatuses = []string{"available", "reserved", "for_repair", "in_service", "damaged"}
+
+func GetLocation(locationID string) (models.Location, error) {
+	location, err := dbHelper.GetLocation(locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return location, ErrLocationNotFound
+	}
+	return location, err
+}
+
+func CreateLocation(request models.CreateLocationRequest) (string, error) {
+	if request.Kind != "site" {
+		parent, err := GetLocation(request.ParentID)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(locationParents[request.Kind], parent.Kind) {
+			return "", fmt.Errorf("%w: a %s cannot sit in a %s", ErrInvalidLocationParent, request.Kind, parent.Kind)
+		}
+	}
+	taken, err := dbHelper.IsLocationNameTaken(request.Name, request.ParentID, "")
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrLocationExists
+	}
+	return dbHelper.CreateLocation(request)
+}
+
+func UpdateLocation(locationID string, request models.UpdateLocationRequest) error {
+	location, err := GetLocation(locationID)
+	if err != nil {
+		return err
+	}
+	parentID := ""
+	if location.ParentID != nil {
+		parentID = *location.ParentID
+	}
+	taken, err := dbHelper.IsLocationNameTaken(request.Name, parentID, locationID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrLocationExists
+	}
+	return dbHelper.UpdateLocation(locationID, request)
+}
+
+// ArchiveLocation archives an empty location. Assets and locations below it have to be moved or
+// archived first.
+func ArchiveLocation(locationID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if _, err := lockLocation(tx, locationID); err != nil {
+			return err
+		}
+		inUse, err := dbHelper.IsLocationInUse(tx, locationID)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrLocationInUse
+		}
+		return dbHelper.ArchiveLocation(tx, locationID)
+	})
+}
+
+func lockLocation(tx *sqlx.Tx, locationID string) (string, error) {
+	kind, err := dbHelper.GetLocationKindForUpdate(tx, locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrLocationNotFound
+	}
+	return kind, err
+}
+
+// moveAsset changes the location of a locked asset, recording the movement. Nothing is recorded
+// when the asset is already there. An empty location takes the asset off any location.
+func moveAsset(tx *sqlx.Tx, assetID, locationID, reason, movedBy, notes string) error {
+	from, err := dbHelper.GetAssetLocation(tx, assetID)
+	if err != nil {
+		return err
+	}
+	var to *string
+	if locationID != "" {
+		if _, err := lockLocation(tx, locationID); err != nil {
+			return err
+		}
+		to = &locationID
+	}
+	if (from == nil && to == nil) || (from != nil && to != nil && *from == *to) {
+		return nil
+	}
+	return dbHelper.MoveAsset(tx, assetID, from, to, reason, movedBy, notes)
+}
+
+// PlaceNewAsset puts an asset being created at its first location.
+func PlaceNewAsset(tx *sqlx.Tx, assetID, locationID, receivedBy string) error {
+	return moveAsset(tx, assetID, locationID, "received", receivedBy, "")
+}
+
+// MoveAsset moves an asset in stock to another location.
+func MoveAsset(assetID, movedBy string, request models.MoveAssetRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		status, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(stockStatuses, status) {
+			return ErrAssetNotMovable
+		}
+		return moveAsset(tx, assetID, request.LocationID, "moved", movedBy, request.Notes)
+	})
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor authentication not enrolled")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrMFARequired       = errors.New("two-factor authentication is required for this role")
+)
+
+const (
+	mfaIssuer = "storex"
+	// MFAChallengeTTL is how long the password step of a login stays valid while waiting for the code.
+	MFAChallengeTTL   = 5 * time.Minute
+	totpPeriod        = 30
+	recoveryCodeCount = 10
+)
+
+func EnrollMFA(userID string) (models.MFAEnrollment, error) {
+	user, err := dbHelper.GetUserByID(userID)
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	keyring, err := utils.MasterKeyring()
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: mfaIssuer, AccountName: user.Email, Period: totpPeriod})
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	ciphertext, wrappedKey, keyID, err := keyring.Encrypt([]byte(key.Secret()))
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	saved, err := dbHelper.SavePendingMFA(userID, ciphertext, wrappedKey, keyID)
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	if !saved {
+		return models.MFAEnrollment{}, ErrMFAAlreadyEnabled
+	}
+
+	qr, err := key.Image(256, 256)
+	if err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, qr); err != nil {
+		return models.MFAEnrollment{}, err
+	}
+	return models.MFAEnrollment{
+		OtpauthURI: key.URL(),
+		QRCode:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// matchTOTP returns the time step a code is valid for at now, allowing one step of clock drift. Steps up
+// to lastUsedStep are refused, so an observed code cannot be replayed.
+func matchTOTP(secret, code string, now time.Time, lastUsedStep int64) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current - 1, current, current + 1} {
+		if step <= lastUsedStep {
+			continue
+		}
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err == nil && subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// lockMFA loads the MFA secret of a user for update and decrypts it.
+func lockMFA(tx *sqlx.Tx, userID string) (models.UserMFA, string, error) {
+	mfa, err := dbHelper.GetUserMFAForUpdate(tx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mfa, "", ErrMFANotEnrolled
+	}
+	if err != nil {
+		return mfa, "", err
+	}
+	keyring, err := utils.MasterKeyring()
+	if err != nil {
+		return mfa, "", err
+	}
+	secret, err := keyring.Decrypt(mfa.Ciphertext, mfa.WrappedKey, mfa.KeyID)
+	if err != nil {
+		return mfa, "", err
+	}
+	return mfa, string(secret), nil
+}
+
+// verifyEnabledMFA checks a TOTP code for a user with MFA enabled and burns its time step.
+func verifyEnabledMFA(tx *sqlx.Tx, userID, code string) error {
+	mfa, secret, err := lockMFA(tx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa.ConfirmedAt == nil {
+		return ErrMFANotEnrolled
+	}
+	step, ok := matchTOTP(secret, code, time.Now(), mfa.LastUsedStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return dbHelper.UpdateMFALastUsedStep(tx, userID, step)
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// newRecoveryCodes returns codes formatted for the user and their hashes for storage.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, utils.HashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// ConfirmMFA enables MFA once the user proves the authenticator works, marks the current session
+// as verified and returns the recovery codes, which are only shown this once.
+func ConfirmMFA(userID, sessionID, code string) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		mfa, secret, err := lockMFA(tx, userID)
+		if err != nil {
+			return err
+		}
+		if mfa.ConfirmedAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+		step, ok := matchTOTP(secret, code, time.Now(), mfa.LastUsedStep)
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		if err := dbHelper.ConfirmUserMFA(tx, userID, step); err != nil {
+			return err
+		}
+		if err := dbHelper.ReplaceRecoveryCodes(tx, userID, hashes); err != nil {
+			return err
+		}
+		return dbHelper.MarkSessionMFAVerified(tx, sessionID)
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return codes, nil
+}
+
+func RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		if err := verifyEnabledMFA(tx, userID, code); err != nil {
+			return err
+		}
+		return dbHelper.ReplaceRecoveryCodes(tx, userID, hashes)
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return codes, nil
+}
+
+// DisableMFA turns MFA off for a user whose role does not require it.
+func DisableMFA(userID, role, code string) error {
+	required, err := dbHelper.IsMFARequired(role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequired
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := verifyEnabledMFA(tx, userID, code); err != nil {
+			return err
+		}
+		return dbHelper.DeleteUserMFA(tx, userID)
+	})
+}
+
+// ResetUserMFA removes the MFA of a user who lost their authenticator and logs them out, so
+// they enroll again on their next login.
+func ResetUserMFA(userID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := dbHelper.DeleteUserMFA(tx, userID); err != nil {
+			return err
+		}
+		return dbHelper.RevokeUserSessions(tx, userID, "mfa_reset")
+	})
+}
+
+// BeginMFAChallenge is the first step of a login with MFA enabled. The returned token stands in
+// for the password step until the code is verified.
+func BeginMFAChallenge(userID string) (models.MFAChallenge, error) {
+	token, err := issueUserToken(userID, "mfa_challenge", MFAChallengeTTL)
+	if err != nil {
+		return models.MFAChallenge{}, err
+	}
+	return models.MFAChallenge{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int(MFAChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteMFALogin finishes a login with a TOTP or recovery code. The challenge token is used up
+// even when the code is wrong, so guessing codes requires the password every time.
+func CompleteMFALogin(mfaToken, code, recoveryCode, userAgent, ipAddress string) (models.TokenResponse, error) {
+	var userID string
+	failed := false
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		challenge, err := consumeUserToken(tx, "mfa_challenge", mfaToken)
+		if err != nil {
+			return err
+		}
+		userID = challenge.UserID
+
+		if code != "" {
+			err = verifyEnabledMFA(tx, userID, code)
+		} else {
+			var used bool
+			used, err = dbHelper.UseRecoveryCode(tx, userID, utils.HashToken(normalizeRecoveryCode(recoveryCode)))
+			if err == nil && !used {
+				err = ErrInvalidMFACode
+			}
+		}
+		if errors.Is(err, ErrInvalidMFACode) {
+			failed = true
+			return nil
+		}
+		return err
+	})
+	if txErr != nil {
+		return models.TokenResponse{}, txErr
+	}
+	if failed {
+		return models.TokenResponse{}, ErrInvalidMFACode
+	}
+
+	user, err := dbHelper.GetUserByID(userID)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	return StartSession(user.ID, user.Role, userAgent, ipAddress, true)
+}
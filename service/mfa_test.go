@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func totpCode(t *testing.T, step int64) string {
+	t.Helper()
+	code, err := totp.GenerateCodeCustom(testTOTPSecret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		t.Fatalf("GenerateCodeCustom: %v", err)
+	}
+	return code
+}
+
+func TestMatchTOTP(t *testing.T) {
+	const current int64 = 58000000
+	// a few seconds into the current step
+	now := time.Unix(current*totpPeriod+7, 0)
+
+	tests := []struct {
+		name         string
+		codeStep     int64
+		lastUsedStep int64
+		wantStep     int64
+		wantOK       bool
+	}{
+		{name: "current step", codeStep: current, lastUsedStep: 0, wantStep: current, wantOK: true},
+		{name: "previous step within drift", codeStep: current - 1, lastUsedStep: 0, wantStep: current - 1, wantOK: true},
+		{name: "next step within drift", codeStep: current + 1, lastUsedStep: 0, wantStep: current + 1, wantOK: true},
+		{name: "two steps behind", codeStep: current - 2, lastUsedStep: 0},
+		{name: "two steps ahead", codeStep: current + 2, lastUsedStep: 0},
+		{name: "replay of the used step", codeStep: current, lastUsedStep: current},
+		{name: "older step after a newer one was used", codeStep: current - 1, lastUsedStep: current},
+		{name: "step after the used one", codeStep: current + 1, lastUsedStep: current, wantStep: current + 1, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := matchTOTP(testTOTPSecret, totpCode(t, tt.codeStep), now, tt.lastUsedStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("matchTOTP = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatchTOTPRejectsWrongCode(t *testing.T) {
+	now := time.Unix(58000000*totpPeriod, 0)
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := matchTOTP(testTOTPSecret, code, now, 0); ok {
+			t.Errorf("matchTOTP(%q) matched", code)
+		}
+	}
+}
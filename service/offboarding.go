@@ -0,0 +1,116 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrOffboardingNotFound   = errors.New("offboarding not found")
+	ErrOffboardingInProgress = errors.New("user is already being offboarded")
+	ErrReturnTaskNotFound    = errors.New("return task not found")
+	ErrReturnTaskResolved    = errors.New("return task already resolved")
+	ErrAssetsOutstanding     = errors.New("user still holds assets, they must be returned or written off first")
+	ErrUserOffboarding       = errors.New("user is being offboarded and cannot receive assets")
+	ErrOffboardingLogin      = errors.New("user is being offboarded and cannot log in")
+)
+
+// StartOffboarding opens the offboarding of a leaver: a return task is created for every asset
+// they hold, assets reserved for them are released and their sessions are revoked. A leaver holding nothing is cleared straight away.
+func StartOffboarding(actorID, userID string, request models.StartOffboardingRequest) (string, error) {
+	if actorID == userID {
+		return "", ErrSelfManagement
+	}
+	if _, err := getActiveUser(userID); err != nil {
+		return "", err
+	}
+	var offboardingID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		offboarding, err := dbHelper.IsOffboarding(tx, userID)
+		if err != nil {
+			return err
+		}
+		if offboarding {
+			return ErrOffboardingInProgress
+		}
+		offboardingID, err = dbHelper.CreateOffboarding(tx, userID, actorID, request)
+		if err != nil {
+			return err
+		}
+		if _, err := dbHelper.CreateReturnTasks(tx, offboardingID, userID); err != nil {
+			return err
+		}
+		reserved, err := dbHelper.CancelUserReservations(tx, userID, actorID)
+		if err != nil {
+			return err
+		}
+		if err := dbHelper.ReleaseReservedAssets(tx, reserved); err != nil {
+			return err
+		}
+		if err := dbHelper.RevokeUserSessions(tx, userID, "offboarding"); err != nil {
+			return err
+		}
+		return dbHelper.ClearOffboardingIfDone(tx, offboardingID)
+	})
+	return offboardingID, txErr
+}
+
+// resolveReturnTask resolves the return task of an asset leaving its holder, when the holder is
+// being offboarded, and clears the offboarding once nothing is left pending.
+func resolveReturnTask(tx *sqlx.Tx, assetID, resolvedBy, status, notes string) error {
+	offboardingID, err := dbHelper.ResolveReturnTask(tx, assetID, resolvedBy, status, notes)
+	if err != nil || offboardingID == "" {
+		return err
+	}
+	return dbHelper.ClearOffboardingIfDone(tx, offboardingID)
+}
+
+// WriteOffAsset resolves a return task the leaver cannot fulfil by reporting the asset lost.
+func WriteOffAsset(offboardingID, taskID, actorID, notes string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		task, err := dbHelper.GetReturnTaskForUpdate(tx, offboardingID, taskID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReturnTaskNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if task.Status != "pending" {
+			return ErrReturnTaskResolved
+		}
+		from, err := lockAsset(tx, task.AssetID)
+		if err != nil {
+			return err
+		}
+		if from != "assigned" {
+			return ErrAssetNotAssigned
+		}
+		_, err = recordIncident(tx, task.AssetID, from, actorID, models.ReportIncidentRequest{
+			Kind:       "lost",
+			OccurredOn: time.Now(),
+			Remarks:    notes,
+		})
+		return err
+	})
+}
+
+func GetOffboarding(offboardingID string) (models.OffboardingDetail, error) {
+	offboarding, err := dbHelper.GetOffboarding(offboardingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.OffboardingDetail{}, ErrOffboardingNotFound
+	}
+	if err != nil {
+		return models.OffboardingDetail{}, err
+	}
+	tasks, err := dbHelper.ListReturnTasks(offboardingID)
+	if err != nil {
+		return models.OffboardingDetail{}, err
+	}
+	return models.OffboardingDetail{Offboarding: offboarding, Tasks: tasks}, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationClosed   = errors.New("reservation is no longer active")
+	ErrInvalidReservation  = errors.New("invalid reservation")
+	ErrAssetReserved       = errors.New("asset is reserved for another user")
+	ErrReservationFlow     = errors.New("reservations must go through the reservation endpoints")
+)
+
+// DefaultReservationWindow is how long a reservation stays open past its start date when no
+// expiry is given.
+const DefaultReservationWindow = 7 * 24 * time.Hour
+
+// ReserveAsset holds an available asset for a user from a start date. The asset leaves the
+// available pool until the reservation is converted, cancelled or expires.
+func ReserveAsset(assetID, reservedBy string, request models.ReserveAssetRequest) (string, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	if request.StartsOn.Before(today) {
+		return "", fmt.Errorf("%w: start date is in the past", ErrInvalidReservation)
+	}
+	expiresAt := request.StartsOn.Add(DefaultReservationWindow)
+	if request.ExpiresAt != nil {
+		expiresAt = *request.ExpiresAt
+	}
+	if !expiresAt.After(request.StartsOn) {
+		return "", fmt.Errorf("%w: expiry must be after the start date", ErrInvalidReservation)
+	}
+	if _, err := getActiveUser(request.UserID); err != nil {
+		return "", err
+	}
+
+	var reservationID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if err := CheckTransition(from, "reserved"); err != nil {
+			return err
+		}
+		offboarding, err := dbHelper.IsOffboarding(tx, request.UserID)
+		if err != nil {
+			return err
+		}
+		if offboarding {
+			return ErrUserOffboarding
+		}
+		reservationID, err = dbHelper.CreateReservation(tx, assetID, reservedBy, request, expiresAt)
+		if err != nil {
+			return err
+		}
+		return dbHelper.UpdateAssetStatus(tx, assetID, "reserved")
+	})
+	return reservationID, txErr
+}
+
+func CancelReservation(reservationID, cancelledBy string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		reservation, err := dbHelper.GetReservationForUpdate(tx, reservationID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReservationNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if reservation.Status != "active" {
+			return ErrReservationClosed
+		}
+		if _, err := lockAsset(tx, reservation.AssetID); err != nil {
+			return err
+		}
+		if err := dbHelper.CloseReservation(tx, reservationID, "cancelled", cancelledBy); err != nil {
+			return err
+		}
+		return dbHelper.ReleaseReservedAssets(tx, []string{reservation.AssetID})
+	})
+}
+
+// convertReservation closes the reservation of a reserved asset being assigned. Only the user
+// the asset is reserved for can receive it.
+func convertReservation(tx *sqlx.Tx, assetID, assignedBy, assignedTo string) error {
+	reservation, err := dbHelper.GetActiveReservationForUpdate(tx, assetID)
+	if err != nil {
+		return err
+	}
+	if reservation.ReservedFor != assignedTo {
+		return ErrAssetReserved
+	}
+	return dbHelper.CloseReservation(tx, reservation.ID, "converted", assignedBy)
+}
+
+// ProcessReservations assigns the reserved assets whose start date has come and releases the
+// assets of reservations that expired unconverted. A reservation that fails to convert, say
+// because the user left, is logged and retried on the next run until it expires.
+func ProcessReservations(now time.Time) error {
+	due, err := dbHelper.ListDueReservations(now)
+	if err != nil {
+		return err
+	}
+	for _, reservation := range due {
+		txErr := database.Tx(func(tx *sqlx.Tx) error {
+			locked, err := dbHelper.GetReservationForUpdate(tx, reservation.ID)
+			if err != nil {
+				return err
+			}
+			if locked.Status != "active" {
+				return nil
+			}
+			if _, err := getActiveUser(reservation.ReservedFor); err != nil {
+				return err
+			}
+			return assignAsset(tx, reservation.AssetID, reservation.ReservedBy, reservation.ReservedFor)
+		})
+		if txErr != nil {
+			fmt.Printf("failed to convert reservation %s: %v\n", reservation.ID, txErr)
+		}
+	}
+
+	return database.Tx(func(tx *sqlx.Tx) error {
+		assetIDs, err := dbHelper.ExpireReservations(tx, now)
+		if err != nil || len(assetIDs) == 0 {
+			return err
+		}
+		return dbHelper.ReleaseReservedAssets(tx, assetIDs)
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrRoleExists        = errors.New("role exists")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+	ErrBuiltinRole       = errors.New("builtin roles cannot be deleted")
+	ErrRoleLockout       = errors.New("the admin role must keep role.manage")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// adminRole always keeps the permission to manage roles, so admins cannot lock themselves out.
+const adminRole = "admin"
+
+func ensureRole(name string) error {
+	exist, err := dbHelper.IsRoleExist(name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("%w: %s", ErrUnknownRole, name)
+	}
+	return nil
+}
+
+func checkPermissions(permissions []string) error {
+	unknown, err := dbHelper.UnknownPermissions(permissions)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownPermission, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func CreateRole(request models.RoleRequest) error {
+	if !roleNamePattern.MatchString(request.Name) {
+		return fmt.Errorf("%w: names use lowercase letters, digits and dashes", ErrUnknownRole)
+	}
+	exist, err := dbHelper.IsRoleExist(request.Name)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ErrRoleExists
+	}
+	if err := checkPermissions(request.Permissions); err != nil {
+		return err
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := dbHelper.CreateRole(tx, request.Name, request.Label, request.MFARequired); err != nil {
+			return err
+		}
+		return dbHelper.SetRolePermissions(tx, request.Name, request.Permissions)
+	})
+}
+
+func UpdateRole(name string, request models.UpdateRoleRequest) error {
+	if err := ensureRole(name); err != nil {
+		return err
+	}
+	if err := checkPermissions(request.Permissions); err != nil {
+		return err
+	}
+	if name == adminRole && !slices.Contains(request.Permissions, "role.manage") {
+		return ErrRoleLockout
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := dbHelper.UpdateRole(tx, name, request.Label, request.MFARequired); err != nil {
+			return err
+		}
+		return dbHelper.SetRolePermissions(tx, name, request.Permissions)
+	})
+}
+
+func DeleteRole(name string) error {
+	role, err := dbHelper.GetRole(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownRole
+	}
+	if err != nil {
+		return err
+	}
+	if role.Builtin {
+		return ErrBuiltinRole
+	}
+	users, err := dbHelper.CountUsersWithRole(name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+	return dbHelper.DeleteRole(name)
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secretMarker flags a spec schema property as secret, as in "devicePassword": {"type": "string", "x-secret": true}.
+// Secret values are encrypted at rest and never kept in the asset specs.
+const secretMarker = "x-secret"
+
+// DefaultSecretField is revealed when a reveal request names no field.
+const DefaultSecretField = "devicePassword"
+
+// SecretFields returns the properties of a spec schema flagged as secret.
+func SecretFields(specSchema json.RawMessage) []string {
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(specSchema, &schema); err != nil {
+		return nil
+	}
+	fields := make([]string, 0)
+	for name, property := range schema.Properties {
+		if secret, _ := property[secretMarker].(bool); secret {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// secretRedactor drops the secret fields from specs read back for display. Sealed secrets are
+// not kept in the specs, but without a keyring plaintext values from before encryption remain.
+// The secret fields are looked up once per asset type.
+type secretRedactor map[string][]string
+
+func (r secretRedactor) redact(assetType string, specs json.RawMessage) (json.RawMessage, error) {
+	fields, ok := r[assetType]
+	if !ok {
+		specSchema, err := dbHelper.GetSpecSchema(assetType)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		fields = SecretFields(specSchema)
+		r[assetType] = fields
+	}
+	if len(fields) == 0 || len(specs) == 0 {
+		return specs, nil
+	}
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(specs, &values); err != nil {
+		return nil, fmt.Errorf("invalid specs: %w", err)
+	}
+	for _, field := range fields {
+		delete(values, field)
+	}
+	return json.Marshal(values)
+}
+
+// PreparedSpecs are validated asset specs with the secret fields split out.
+type PreparedSpecs struct {
+	Specs json.RawMessage
+	// fields are all the secret fields of the asset type, secrets the values to encrypt.
+	fields  []string
+	secrets map[string]json.RawMessage
+}
+
+// PrepareSpecs validates the specs of an asset against its type and splits out the secret
+// fields. When updating an existing asset a secret left out of the specs keeps its stored value.
+func PrepareSpecs(assetTypeName, assetID string, specs json.RawMessage) (PreparedSpecs, error) {
+	assetType, err := GetAssetType(assetTypeName)
+	if err != nil {
+		return PreparedSpecs{}, err
+	}
+	fields := SecretFields(assetType.SpecSchema)
+	if len(fields) == 0 {
+		specs, err := validateSpecs(assetType, specs)
+		return PreparedSpecs{Specs: specs, fields: fields}, err
+	}
+
+	values := make(map[string]json.RawMessage)
+	if len(specs) > 0 && string(specs) != "null" {
+		if err := json.Unmarshal(specs, &values); err != nil {
+			return PreparedSpecs{}, fmt.Errorf("%w: %v", ErrInvalidSpecs, err)
+		}
+	}
+
+	kept := make([]string, 0)
+	if assetID != "" {
+		for _, field := range fields {
+			if _, ok := values[field]; ok {
+				continue
+			}
+			value, err := decryptSecret(assetID, field)
+			if errors.Is(err, ErrSecretNotFound) {
+				continue
+			}
+			if err != nil {
+				return PreparedSpecs{}, err
+			}
+			values[field] = value
+			kept = append(kept, field)
+		}
+	}
+
+	merged, err := json.Marshal(values)
+	if err != nil {
+		return PreparedSpecs{}, err
+	}
+	if _, err := validateSpecs(assetType, merged); err != nil {
+		return PreparedSpecs{}, err
+	}
+
+	secrets := make(map[string]json.RawMessage)
+	for _, field := range fields {
+		if value, ok := values[field]; ok && !slices.Contains(kept, field) {
+			secrets[field] = value
+		}
+		delete(values, field)
+	}
+	if len(secrets) > 0 {
+		if _, err := utils.MasterKeyring(); err != nil {
+			return PreparedSpecs{}, err
+		}
+	}
+
+	public, err := json.Marshal(values)
+	if err != nil {
+		return PreparedSpecs{}, err
+	}
+	return PreparedSpecs{Specs: public, fields: fields, secrets: secrets}, nil
+}
+
+// StoreSecrets encrypts the secret fields of the specs for the asset and drops stored secrets
+// the asset type no longer has.
+func (p PreparedSpecs) StoreSecrets(tx *sqlx.Tx, assetID string) error {
+	if err := dbHelper.DeleteAssetSecretsExcept(tx, assetID, p.fields); err != nil {
+		return err
+	}
+	return storeSecrets(tx, assetID, p.secrets)
+}
+
+func storeSecrets(tx *sqlx.Tx, assetID string, secrets map[string]json.RawMessage) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+	keyring, err := utils.MasterKeyring()
+	if err != nil {
+		return err
+	}
+	for field, value := range secrets {
+		ciphertext, wrappedKey, keyID, err := keyring.Encrypt(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", field, err)
+		}
+		if err := dbHelper.UpsertAssetSecret(tx, assetID, field, ciphertext, wrappedKey, keyID); err != nil {
+			return err
+		}
+		// a new value is the rotation a missing asset was waiting for
+		if err := dbHelper.CompleteRotationReminders(tx, assetID, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decryptSecret(assetID, field string) (json.RawMessage, error) {
+	secret, err := dbHelper.GetAssetSecret(assetID, field)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSecretNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := utils.MasterKeyring()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := keyring.Decrypt(secret.Ciphertext, secret.WrappedKey, secret.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return plaintext, nil
+}
+
+// RevealSecret decrypts a secret spec field of an asset. The access is audited, and the value
+// is only returned once the audit record is committed.
+func RevealSecret(assetID, field string, user *models.UserCtx, reason, ipAddress string) (json.RawMessage, error) {
+	value, err := decryptSecret(assetID, field)
+	if err != nil {
+		return nil, err
+	}
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		return dbHelper.CreateSecretAccess(tx, assetID, field, user.UserID, user.SessionID, reason, ipAddress)
+	})
+	if txErr != nil {
+		return nil, fmt.Errorf("failed to audit secret access: %w", txErr)
+	}
+	return value, nil
+}
+
+// RotateSecretKeys re-wraps every data key, of asset secrets and MFA secrets, that is not wrapped
+// by the active master key. The secrets themselves are not re-encrypted.
+func RotateSecretKeys() (int, error) {
+	keyring, err := utils.MasterKeyring()
+	if err != nil {
+		return 0, err
+	}
+	rewrapped := 0
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		secrets, err := dbHelper.ListSecretsToRewrap(tx, keyring.ActiveKeyID())
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			wrappedKey, keyID, err := keyring.Rewrap(secret.WrappedKey, secret.KeyID)
+			if err != nil {
+				return fmt.Errorf("failed to rewrap secret %s: %w", secret.ID, err)
+			}
+			if err := dbHelper.RewrapAssetSecret(tx, secret.ID, wrappedKey, keyID); err != nil {
+				return err
+			}
+		}
+		mfaSecrets, err := dbHelper.ListMFASecretsToRewrap(tx, keyring.ActiveKeyID())
+		if err != nil {
+			return err
+		}
+		for _, secret := range mfaSecrets {
+			wrappedKey, keyID, err := keyring.Rewrap(secret.WrappedKey, secret.KeyID)
+			if err != nil {
+				return fmt.Errorf("failed to rewrap mfa secret of %s: %w", secret.UserID, err)
+			}
+			if err := dbHelper.RewrapMFASecret(tx, secret.UserID, wrappedKey, keyID); err != nil {
+				return err
+			}
+		}
+		rewrapped = len(secrets) + len(mfaSecrets)
+		return nil
+	})
+	return rewrapped, txErr
+}
+
+// SealPlaintextSecrets moves secret fields still stored in plaintext in asset specs, such as
+// device passwords from before encryption, into encrypted storage.
+func SealPlaintextSecrets() (int, error) {
+	assetTypes, err := dbHelper.ListAssetTypes()
+	if err != nil {
+		return 0, err
+	}
+	sealed := 0
+	for _, assetType := range assetTypes {
+		fields := SecretFields(assetType.SpecSchema)
+		if len(fields) == 0 {
+			continue
+		}
+		assets, err := dbHelper.ListAssetsWithPlaintextSecrets(assetType.Name, fields)
+		if err != nil {
+			return sealed, err
+		}
+		for _, asset := range assets {
+			values := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(asset.Specs, &values); err != nil {
+				return sealed, fmt.Errorf("invalid specs on asset %s: %w", asset.ID, err)
+			}
+			secrets := make(map[string]json.RawMessage)
+			for _, field := range fields {
+				if value, ok := values[field]; ok {
+					secrets[field] = value
+					delete(values, field)
+				}
+			}
+			public, err := json.Marshal(values)
+			if err != nil {
+				return sealed, err
+			}
+			txErr := database.Tx(func(tx *sqlx.Tx) error {
+				if err := storeSecrets(tx, asset.ID, secrets); err != nil {
+					return err
+				}
+				return dbHelper.UpdateAssetSpecs(tx, asset.ID, public)
+			})
+			if txErr != nil {
+				return sealed, txErr
+			}
+			sealed++
+		}
+	}
+	return sealed, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrNotYourAsset         = errors.New("asset is not assigned to you")
+	ErrAlreadyAcknowledged  = errors.New("receipt already acknowledged")
+	ErrIssueAlreadyReported = errors.New("issue already reported and pending")
+	ErrIssueNotFound        = errors.New("issue not found")
+	ErrIssueAlreadyResolved = errors.New("issue already resolved")
+)
+
+// MyAssets lists the assets currently assigned to the user, with secret specs left out.
+func MyAssets(userID string) ([]models.MyAsset, error) {
+	assets, err := dbHelper.MyAssets(userID)
+	if err != nil {
+		return nil, err
+	}
+	redactor := make(secretRedactor)
+	for i := range assets {
+		if assets[i].Specs, err = redactor.redact(assets[i].AssetType, assets[i].Specs); err != nil {
+			return nil, err
+		}
+	}
+	return assets, nil
+}
+
+func AcknowledgeAsset(assetID, userID string) error {
+	assigned, err := dbHelper.IsAssignedTo(assetID, userID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return ErrNotYourAsset
+	}
+	acknowledged, err := dbHelper.AcknowledgeAssignment(assetID, userID)
+	if err != nil {
+		return err
+	}
+	if !acknowledged {
+		return ErrAlreadyAcknowledged
+	}
+	return nil
+}
+
+// ReportIssue records a problem with an asset assigned to the user, for asset managers to act on.
+func ReportIssue(assetID, userID string, request models.ReportIssueRequest) (string, error) {
+	assigned, err := dbHelper.IsAssignedTo(assetID, userID)
+	if err != nil {
+		return "", err
+	}
+	if !assigned {
+		return "", ErrNotYourAsset
+	}
+	pending, err := dbHelper.HasPendingIssue(assetID, request.Kind)
+	if err != nil {
+		return "", err
+	}
+	if pending {
+		return "", ErrIssueAlreadyReported
+	}
+	return dbHelper.CreateAssetIssue(assetID, userID, request)
+}
+
+func ResolveIssue(issueID, resolvedBy string, request models.ResolveIssueRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		issue, err := dbHelper.GetAssetIssueForUpdate(tx, issueID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrIssueNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if issue.Status != "pending" {
+			return ErrIssueAlreadyResolved
+		}
+		return dbHelper.ResolveAssetIssue(tx, issueID, resolvedBy, request.Status, request.Notes)
+	})
+}
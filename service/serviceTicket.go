@@ -0,0 +1,151 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrTicketNotFound    = errors.New("service ticket not found")
+	ErrTicketClosed      = errors.New("service ticket is already closed")
+	ErrTicketAlreadyOpen = errors.New("asset already has an open service ticket")
+	ErrServiceTicketFlow = errors.New("asset has an open service ticket, its status changes through the ticket")
+)
+
+// ticketTransitions lists the statuses a service ticket may move to before it is closed.
+var ticketTransitions = map[string][]string{
+	"open":           {"in_progress"},
+	"in_progress":    {"awaiting_parts"},
+	"awaiting_parts": {"in_progress"},
+}
+
+func lockServiceTicket(tx *sqlx.Tx, ticketID string) (models.ServiceTicket, error) {
+	ticket, err := dbHelper.GetServiceTicketForUpdate(tx, ticketID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ticket, ErrTicketNotFound
+	}
+	if err == nil && ticket.Status == "closed" {
+		return ticket, ErrTicketClosed
+	}
+	return ticket, err
+}
+
+// checkTicketAsset refuses assets whose status is driven by an incident or a disposal, so a
+// ticket cannot bring a lost, stolen or disposed asset back without going through them.
+func checkTicketAsset(from string) error {
+	if slices.Contains(missingAssetStatuses, from) {
+		return ErrIncidentFlow
+	}
+	if from == "disposed" {
+		return ErrDisposalFlow
+	}
+	return nil
+}
+
+// OpenServiceTicket opens a ticket for the asset and sends it for repair.
+func OpenServiceTicket(assetID, openedBy string, request models.OpenServiceTicketRequest) (string, error) {
+	var ticketID string
+	err := database.Tx(func(tx *sqlx.Tx) error {
+		from, err := lockAsset(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if from == "assigned" {
+			return ErrAssignmentFlow
+		}
+		if err := checkTicketAsset(from); err != nil {
+			return err
+		}
+		exists, err := dbHelper.HasOpenServiceTicket(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrTicketAlreadyOpen
+		}
+		if from != "for_repair" {
+			if err := CheckTransition(from, "for_repair"); err != nil {
+				return err
+			}
+			if err := dbHelper.UpdateAssetStatus(tx, assetID, "for_repair"); err != nil {
+				return err
+			}
+		}
+		ticketID, err = dbHelper.CreateServiceTicket(tx, assetID, openedBy, request)
+		return err
+	})
+	return ticketID, err
+}
+
+// UpdateServiceTicket changes the ticket details and status. Work starting on the ticket
+// moves the asset from for_repair to in_service.
+func UpdateServiceTicket(ticketID string, request models.UpdateServiceTicketRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		ticket, err := lockServiceTicket(tx, ticketID)
+		if err != nil {
+			return err
+		}
+		if request.Status != "" && request.Status != ticket.Status {
+			allowed := ticketTransitions[ticket.Status]
+			if !slices.Contains(allowed, request.Status) {
+				return &TransitionError{Subject: "service ticket", From: ticket.Status, To: request.Status, Allowed: allowed}
+			}
+			if request.Status == "in_progress" {
+				from, err := lockAsset(tx, ticket.AssetID)
+				if err != nil {
+					return err
+				}
+				if err := checkTicketAsset(from); err != nil {
+					return err
+				}
+				if from != "in_service" {
+					if err := CheckTransition(from, "in_service"); err != nil {
+						return err
+					}
+					if err := dbHelper.UpdateAssetStatus(tx, ticket.AssetID, "in_service"); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return dbHelper.UpdateServiceTicket(tx, ticketID, request)
+	})
+}
+
+// CloseServiceTicket records the final invoice and returns the asset to the outcome status.
+// A ticket closed before work was started on it passes the asset through in_service, so a
+// repair recorded only at closing can still return the asset to stock.
+func CloseServiceTicket(ticketID, closedBy string, request models.CloseServiceTicketRequest) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		ticket, err := lockServiceTicket(tx, ticketID)
+		if err != nil {
+			return err
+		}
+		from, err := lockAsset(tx, ticket.AssetID)
+		if err != nil {
+			return err
+		}
+		if err := checkTicketAsset(from); err != nil {
+			return err
+		}
+		if from == "for_repair" && request.Outcome == "available" {
+			if err := CheckTransition(from, "in_service"); err != nil {
+				return err
+			}
+			from = "in_service"
+		}
+		if err := CheckTransition(from, request.Outcome); err != nil {
+			return err
+		}
+		if err := dbHelper.UpdateAssetStatus(tx, ticket.AssetID, request.Outcome); err != nil {
+			return err
+		}
+		return dbHelper.CloseServiceTicket(tx, ticketID, closedBy, request)
+	})
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, session revoked")
+)
+
+const (
+	// SessionTTL bounds how long a login can be kept alive by refreshing.
+	SessionTTL      = 30 * 24 * time.Hour
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+func issueRefreshToken(tx *sqlx.Tx, sessionID string, sessionExpiresAt time.Time) (string, error) {
+	token, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	expiresAt := time.Now().Add(RefreshTokenTTL)
+	if expiresAt.After(sessionExpiresAt) {
+		expiresAt = sessionExpiresAt
+	}
+	if err := dbHelper.CreateRefreshToken(tx, sessionID, tokenHash, expiresAt); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func tokenResponse(userID, sessionID, role, refreshToken string) (models.TokenResponse, error) {
+	token, err := utils.GenerateJWT(userID, sessionID, role)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	return models.TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// refuseOffboarding stops a leaver from getting a session back once their offboarding started.
+func refuseOffboarding(tx *sqlx.Tx, userID string) error {
+	offboarding, err := dbHelper.IsOffboarding(tx, userID)
+	if err != nil {
+		return err
+	}
+	if offboarding {
+		return ErrOffboardingLogin
+	}
+	return nil
+}
+
+// StartSession creates a session for a user who just authenticated and issues its first tokens.
+// mfaVerified records whether the login passed a second factor.
+func StartSession(userID, role, userAgent, ipAddress string, mfaVerified bool) (models.TokenResponse, error) {
+	var sessionID, refreshToken string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		if err := refuseOffboarding(tx, userID); err != nil {
+			return err
+		}
+		expiresAt := time.Now().Add(SessionTTL)
+		var err error
+		sessionID, err = dbHelper.CreateUserSession(tx, userID, userAgent, ipAddress, expiresAt, mfaVerified)
+		if err != nil {
+			return err
+		}
+		refreshToken, err = issueRefreshToken(tx, sessionID, expiresAt)
+		return err
+	})
+	if txErr != nil {
+		return models.TokenResponse{}, txErr
+	}
+	return tokenResponse(userID, sessionID, role, refreshToken)
+}
+
+// RefreshSession rotates a refresh token. A refresh token can be used once; presenting it again
+// means it leaked, so the whole session is revoked.
+func RefreshSession(refreshToken, userAgent, ipAddress string) (models.TokenResponse, error) {
+	var current models.RefreshToken
+	var rotated string
+	reused := false
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		var err error
+		current, err = dbHelper.GetRefreshTokenForUpdate(tx, utils.HashToken(refreshToken))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return err
+		}
+		if current.SessionRevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+		if current.UsedAt != nil {
+			// committed, so the revocation sticks although the refresh fails
+			reused = true
+			return dbHelper.RevokeSession(tx, current.SessionID, "refresh_token_reuse")
+		}
+		now := time.Now()
+		if now.After(current.ExpiresAt) || now.After(current.SessionExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+		if err := refuseOffboarding(tx, current.UserID); err != nil {
+			return err
+		}
+
+		if err := dbHelper.MarkRefreshTokenUsed(tx, current.ID); err != nil {
+			return err
+		}
+		if err := dbHelper.TouchSession(tx, current.SessionID, userAgent, ipAddress); err != nil {
+			return err
+		}
+		rotated, err = issueRefreshToken(tx, current.SessionID, current.SessionExpiresAt)
+		return err
+	})
+	if txErr != nil {
+		return models.TokenResponse{}, txErr
+	}
+	if reused {
+		return models.TokenResponse{}, ErrRefreshTokenReused
+	}
+	return tokenResponse(current.UserID, current.SessionID, current.Role, rotated)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/models"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferClosed   = errors.New("transfer is not waiting for this step")
+	ErrTransferPending  = errors.New("asset already has a pending transfer")
+	ErrTransferToSelf   = errors.New("cannot transfer an asset to yourself")
+)
+
+// InitiateTransfer starts the hand over of an asset from its holder to another employee, who
+// has to accept it.
+func InitiateTransfer(assetID, fromUser string, request models.TransferRequest) (string, error) {
+	if request.ToUserID == fromUser {
+		return "", ErrTransferToSelf
+	}
+	if _, err := getActiveUser(request.ToUserID); err != nil {
+		return "", err
+	}
+	var transferID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		if _, err := lockAsset(tx, assetID); err != nil {
+			return err
+		}
+		assigned, err := dbHelper.IsAssignedToTx(tx, assetID, fromUser)
+		if err != nil {
+			return err
+		}
+		if !assigned {
+			return ErrNotYourAsset
+		}
+		open, err := dbHelper.HasOpenTransfer(tx, assetID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrTransferPending
+		}
+		if err := checkNotOffboarding(tx, fromUser, request.ToUserID); err != nil {
+			return err
+		}
+		transferID, err = dbHelper.CreateTransfer(tx, assetID, fromUser, request)
+		return err
+	})
+	return transferID, txErr
+}
+
+func checkNotOffboarding(tx *sqlx.Tx, userIDs ...string) error {
+	for _, userID := range userIDs {
+		offboarding, err := dbHelper.IsOffboarding(tx, userID)
+		if err != nil {
+			return err
+		}
+		if offboarding {
+			return ErrUserOffboarding
+		}
+	}
+	return nil
+}
+
+func lockTransfer(tx *sqlx.Tx, transferID string) (models.AssetTransfer, error) {
+	transfer, err := dbHelper.GetTransferForUpdate(tx, transferID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return transfer, ErrTransferNotFound
+	}
+	return transfer, err
+}
+
+// completeTransfer closes the assignment of the holder and opens one for the recipient, so both
+// sides of the hand over are in the assignment history. The new assignment is made by the asset
+// manager who approved the transfer, or by the holder when no approval was needed.
+func completeTransfer(tx *sqlx.Tx, transfer models.AssetTransfer, approvedBy string) error {
+	from, err := lockAsset(tx, transfer.AssetID)
+	if err != nil {
+		return err
+	}
+	if from != "assigned" {
+		return ErrAssetNotAssigned
+	}
+	assigned, err := dbHelper.IsAssignedToTx(tx, transfer.AssetID, transfer.FromUser)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return ErrNotYourAsset
+	}
+	if err := checkNotOffboarding(tx, transfer.FromUser, transfer.ToUser); err != nil {
+		return err
+	}
+	if err := dbHelper.CloseAssignment(tx, transfer.AssetID, transfer.ToUser, "", "transferred to "+transfer.ToUserName); err != nil {
+		return err
+	}
+	assignedBy := transfer.FromUser
+	if approvedBy != "" {
+		assignedBy = approvedBy
+	}
+	if err := dbHelper.AssignedAssets(tx, transfer.AssetID, assignedBy, transfer.ToUser); err != nil {
+		return err
+	}
+	if err := dbHelper.CreateAssignment(tx, transfer.AssetID, transfer.ToUser, assignedBy); err != nil {
+		return err
+	}
+	// accepting the transfer is the recipient's receipt
+	if err := dbHelper.AcknowledgeTransferredAssignment(tx, transfer.AssetID, transfer.ID); err != nil {
+		return err
+	}
+	return dbHelper.CloseTransfer(tx, transfer.ID, "completed")
+}
+
+// RespondToTransfer is the answer of the recipient. An accepted transfer completes straight away,
+// unless the asset type requires an asset manager to approve it.
+func RespondToTransfer(transferID, userID string, accept bool) (string, error) {
+	var status string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		transfer, err := lockTransfer(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if transfer.ToUser != userID {
+			return ErrTransferNotFound
+		}
+		if transfer.Status != "pending_recipient" {
+			return ErrTransferClosed
+		}
+		if !accept {
+			status = "declined"
+			return dbHelper.CloseTransfer(tx, transferID, status)
+		}
+		if err := dbHelper.AcceptTransfer(tx, transferID); err != nil {
+			return err
+		}
+		assetType, err := GetAssetType(transfer.AssetType)
+		if err != nil {
+			return err
+		}
+		if assetType.TransferRequiresApproval {
+			status = "pending_approval"
+			return dbHelper.UpdateTransferStatus(tx, transferID, status)
+		}
+		status = "completed"
+		return completeTransfer(tx, transfer, "")
+	})
+	return status, txErr
+}
+
+// CancelTransfer withdraws a transfer of the holder before it completes.
+func CancelTransfer(transferID, userID string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		transfer, err := lockTransfer(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if transfer.FromUser != userID {
+			return ErrTransferNotFound
+		}
+		if transfer.Status != "pending_recipient" && transfer.Status != "pending_approval" {
+			return ErrTransferClosed
+		}
+		return dbHelper.CloseTransfer(tx, transferID, "cancelled")
+	})
+}
+
+// DecideTransfer approves or rejects an accepted transfer waiting for an asset manager.
+func DecideTransfer(transferID, decidedBy string, approve bool, notes string) error {
+	return database.Tx(func(tx *sqlx.Tx) error {
+		transfer, err := lockTransfer(tx, transferID)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != "pending_approval" {
+			return ErrTransferClosed
+		}
+		if err := dbHelper.DecideTransfer(tx, transferID, decidedBy, notes); err != nil {
+			return err
+		}
+		if !approve {
+			return dbHelper.CloseTransfer(tx, transferID, "rejected")
+		}
+		return completeTransfer(tx, transfer, decidedBy)
+	})
+}
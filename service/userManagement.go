@@ -0,0 +1,181 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nikhilpratapgit/storex/database"
+	"github.com/nikhilpratapgit/storex/database/dbHelper"
+	"github.com/nikhilpratapgit/storex/mailer"
+	"github.com/nikhilpratapgit/storex/models"
+	"github.com/nikhilpratapgit/storex/utils"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrEmailTaken       = errors.New("email belongs to another active user")
+	ErrInviteNotPending = errors.New("no pending invitation for user")
+	ErrSelfManagement   = errors.New("admins cannot change their own role or archive themselves")
+)
+
+// DefaultRole is given to users who register themselves.
+const DefaultRole = "employee"
+
+const InvitationTTL = 7 * 24 * time.Hour
+
+func getActiveUser(userID string) (models.User, error) {
+	user, err := dbHelper.GetUserByID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, ErrUserNotFound
+	}
+	return user, err
+}
+
+// lockActiveUser locks a user against archiving for the rest of the transaction. Assigning an
+// asset takes a shared lock, so it cannot land between the held asset check and the archiving.
+func lockActiveUser(tx *sqlx.Tx, userID string, exclusive bool) error {
+	err := dbHelper.LockActiveUser(tx, userID, exclusive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// InviteUser creates an account with the role chosen by the admin and mails the invitation.
+func InviteUser(request models.InviteUserRequest, invitedBy string) (string, error) {
+	exist, err := dbHelper.IsUserExist(request.Email)
+	if err != nil {
+		return "", err
+	}
+	if exist {
+		return "", ErrEmailTaken
+	}
+	if err := ensureRole(request.Role); err != nil {
+		return "", err
+	}
+	customFields, err := ValidateCustomFields("user", request.CustomFields)
+	if err != nil {
+		return "", err
+	}
+
+	var userID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		userID, err = dbHelper.CreateInvitedUser(tx, request, customFields, invitedBy)
+		return err
+	})
+	if txErr != nil {
+		return "", txErr
+	}
+	return userID, ResendInvite(userID)
+}
+
+// ResendInvite mails a new invitation link, the links sent before stop working.
+func ResendInvite(userID string) error {
+	user, err := dbHelper.GetPendingInvite(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInviteNotPending
+	}
+	if err != nil {
+		return err
+	}
+	token, err := issueUserToken(user.ID, "invitation", InvitationTTL)
+	if err != nil {
+		return err
+	}
+	return mailer.Default().Send(mailer.Message{
+		To:      user.Email,
+		Subject: "You have been invited to storex",
+		Body: fmt.Sprintf("Hi %s,\n\nAn account has been created for you on storex. Use the link below to set your password. It expires in %s.\n\n%s",
+			user.Name, InvitationTTL, appLink("/accept-invite", token)),
+	})
+}
+
+// AcceptInvite sets the password of an invited user and logs them in.
+func AcceptInvite(token, password, userAgent, ipAddress string) (models.TokenResponse, error) {
+	hashPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	var userID string
+	txErr := database.Tx(func(tx *sqlx.Tx) error {
+		invitation, err := consumeUserToken(tx, "invitation", token)
+		if err != nil {
+			return err
+		}
+		userID = invitation.UserID
+		return dbHelper.AcceptInvite(tx, userID, hashPassword)
+	})
+	if txErr != nil {
+		return models.TokenResponse{}, txErr
+	}
+	user, err := getActiveUser(userID)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	return StartSession(user.ID, user.Role, userAgent, ipAddress, false)
+}
+
+// ChangeUserRole updates the role of a user. Their sessions are revoked, so the new role applies
+// from their next login rather than when their access token expires.
+func ChangeUserRole(actorID, userID, role string) error {
+	if actorID == userID {
+		return ErrSelfManagement
+	}
+	if err := ensureRole(role); err != nil {
+		return err
+	}
+	if _, err := getActiveUser(userID); err != nil {
+		return err
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := dbHelper.UpdateUserRole(tx, userID, role); err != nil {
+			return err
+		}
+		return dbHelper.RevokeUserSessions(tx, userID, "role_changed")
+	})
+}
+
+// ArchiveUser archives a user who holds no assets, leavers are offboarded first.
+func ArchiveUser(actorID, userID string) error {
+	if actorID == userID {
+		return ErrSelfManagement
+	}
+	return database.Tx(func(tx *sqlx.Tx) error {
+		if err := lockActiveUser(tx, userID, true); err != nil {
+			return err
+		}
+		held, err := dbHelper.CountHeldAssets(tx, userID)
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			return ErrAssetsOutstanding
+		}
+		if err := dbHelper.ArchiveUser(tx, userID, actorID); err != nil {
+			return err
+		}
+		return dbHelper.RevokeUserSessions(tx, userID, "user_archived")
+	})
+}
+
+// UnarchiveUser restores an archived user, unless their email was given to another user since.
+func UnarchiveUser(userID string) error {
+	user, err := dbHelper.GetArchivedUser(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	exist, err := dbHelper.IsUserExist(user.Email)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ErrEmailTaken
+	}
+	return dbHelper.UnarchiveUser(userID)
+}
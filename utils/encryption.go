@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var ErrKeyringNotConfigured = errors.New("encryption master keys are not configured")
+
+// Keyring holds the master keys used for envelope encryption. Every secret is encrypted with
+// its own random data key, and the data key is stored wrapped (encrypted) by a master key.
+// Rotating a master key then only means re-wrapping the data keys.
+type Keyring struct {
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+var (
+	keyring     *Keyring
+	keyringErr  error
+	keyringOnce sync.Once
+)
+
+// MasterKeyring loads the keyring from MASTER_KEYS, a comma separated list of id:base64
+// AES-256 keys, and MASTER_KEY_ID, the key new data keys are wrapped with.
+func MasterKeyring() (*Keyring, error) {
+	keyringOnce.Do(func() {
+		keyring, keyringErr = ParseKeyring(os.Getenv("MASTER_KEYS"), os.Getenv("MASTER_KEY_ID"))
+	})
+	return keyring, keyringErr
+}
+
+func ParseKeyring(masterKeys, activeKeyID string) (*Keyring, error) {
+	if masterKeys == "" || activeKeyID == "" {
+		return nil, ErrKeyringNotConfigured
+	}
+	ring := &Keyring{activeKeyID: activeKeyID, keys: make(map[string][]byte)}
+	for _, entry := range strings.Split(masterKeys, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid master key entry %q, expected id:base64key", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key %s: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("master key %s must be 32 bytes", id)
+		}
+		ring.keys[id] = key
+	}
+	if _, ok := ring.keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active master key %s is not in MASTER_KEYS", activeKeyID)
+	}
+	return ring, nil
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKeyID
+}
+
+// seal encrypts with AES-256-GCM and prefixes the nonce to the ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (k *Keyring) masterKey(keyID string) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %s is not configured", keyID)
+	}
+	return key, nil
+}
+
+// Encrypt encrypts plaintext with a fresh data key and returns the ciphertext, the data key
+// wrapped by the active master key and the id of that master key.
+func (k *Keyring) Encrypt(plaintext []byte) (ciphertext, wrappedKey []byte, keyID string, err error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, "", err
+	}
+	ciphertext, err = seal(dataKey, plaintext)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	wrappedKey, err = seal(k.keys[k.activeKeyID], dataKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return ciphertext, wrappedKey, k.activeKeyID, nil
+}
+
+func (k *Keyring) Decrypt(ciphertext, wrappedKey []byte, keyID string) ([]byte, error) {
+	masterKey, err := k.masterKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := open(masterKey, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return open(dataKey, ciphertext)
+}
+
+// Rewrap re-encrypts a data key wrapped by keyID with the active master key.
+func (k *Keyring) Rewrap(wrappedKey []byte, keyID string) ([]byte, string, error) {
+	masterKey, err := k.masterKey(keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	dataKey, err := open(masterKey, wrappedKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	rewrapped, err := seal(k.keys[k.activeKeyID], dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return rewrapped, k.activeKeyID, nil
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, 32))
+}
+
+func testKeyring(t *testing.T, activeKeyID string) *Keyring {
+	t.Helper()
+	ring, err := ParseKeyring("k1:"+testKey(1)+", k2:"+testKey(2), activeKeyID)
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	return ring
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name        string
+		masterKeys  string
+		activeKeyID string
+		wantErr     string
+	}{
+		{name: "valid", masterKeys: "k1:" + testKey(1), activeKeyID: "k1"},
+		{name: "not configured", masterKeys: "", activeKeyID: "", wantErr: ErrKeyringNotConfigured.Error()},
+		{name: "missing id", masterKeys: testKey(1), activeKeyID: "k1", wantErr: "expected id:base64key"},
+		{name: "bad base64", masterKeys: "k1:not-base64!", activeKeyID: "k1", wantErr: "invalid master key k1"},
+		{name: "short key", masterKeys: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), activeKeyID: "k1", wantErr: "must be 32 bytes"},
+		{name: "unknown active key", masterKeys: "k1:" + testKey(1), activeKeyID: "k2", wantErr: "not in MASTER_KEYS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeyring(tt.masterKeys, tt.activeKeyID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseKeyring = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseKeyring = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	ring := testKeyring(t, "k1")
+	plaintext := []byte(`"hunter2"`)
+
+	ciphertext, wrappedKey, keyID, err := ring.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if keyID != "k1" {
+		t.Errorf("Encrypt key id = %s, want k1", keyID)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Errorf("ciphertext contains the plaintext")
+	}
+	got, err := ring.Decrypt(ciphertext, wrappedKey, keyID)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt = %s, want %s", got, plaintext)
+	}
+
+	again, _, _, err := ring.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Equal(again, ciphertext) {
+		t.Errorf("encrypting twice gave the same ciphertext")
+	}
+}
+
+func TestKeyringDecryptFailures(t *testing.T) {
+	ring := testKeyring(t, "k1")
+	ciphertext, wrappedKey, keyID, err := ring.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	flip := func(data []byte) []byte {
+		tampered := bytes.Clone(data)
+		tampered[len(tampered)-1] ^= 0xff
+		return tampered
+	}
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+		wrappedKey []byte
+		keyID      string
+	}{
+		{name: "unknown key id", ciphertext: ciphertext, wrappedKey: wrappedKey, keyID: "k9"},
+		{name: "wrong key id", ciphertext: ciphertext, wrappedKey: wrappedKey, keyID: "k2"},
+		{name: "tampered ciphertext", ciphertext: flip(ciphertext), wrappedKey: wrappedKey, keyID: keyID},
+		{name: "tampered wrapped key", ciphertext: ciphertext, wrappedKey: flip(wrappedKey), keyID: keyID},
+		{name: "truncated ciphertext", ciphertext: ciphertext[:4], wrappedKey: wrappedKey, keyID: keyID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if plaintext, err := ring.Decrypt(tt.ciphertext, tt.wrappedKey, tt.keyID); err == nil {
+				t.Fatalf("Decrypt = %q, want an error", plaintext)
+			}
+		})
+	}
+}
+
+func TestKeyringRewrap(t *testing.T) {
+	old := testKeyring(t, "k1")
+	ciphertext, wrappedKey, keyID, err := old.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotated := testKeyring(t, "k2")
+	rewrapped, newKeyID, err := rotated.Rewrap(wrappedKey, keyID)
+	if err != nil {
+		t.Fatalf("Rewrap: %v", err)
+	}
+	if newKeyID != "k2" {
+		t.Errorf("Rewrap key id = %s, want k2", newKeyID)
+	}
+	got, err := rotated.Decrypt(ciphertext, rewrapped, newKeyID)
+	if err != nil {
+		t.Fatalf("Decrypt after rewrap: %v", err)
+	}
+	if string(got) != "secret" {
+		t.Errorf("Decrypt after rewrap = %s, want secret", got)
+	}
+	if _, err := rotated.Decrypt(ciphertext, rewrapped, "k1"); err == nil {
+		t.Errorf("rewrapped key still opens with the old master key")
+	}
+
+	if _, _, err := rotated.Rewrap(wrappedKey, "k9"); err == nil {
+		t.Errorf("Rewrap with an unknown key id succeeded")
+	}
+	if _, _, err := rotated.Rewrap(wrappedKey, "k2"); err == nil {
+		t.Errorf("Rewrap with the wrong key id succeeded")
+	}
+}
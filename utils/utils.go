@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -62,13 +66,33 @@ func CheckPassword(hashedPassword, plainPassword string) error {
 		[]byte(plainPassword),
 	)
 }
+
+// AccessTokenTTL is kept short, clients renew the access token with their refresh token.
+const AccessTokenTTL = 15 * time.Minute
+
 func GenerateJWT(userID, sessionID, role string) (string, error) {
 	claims := jwt.MapClaims{
 		"userId":    userID,
 		"sessionId": sessionID,
 		"role":      role,
-		"exp":       time.Now().Add(time.Minute * 100).Unix(),
+		"exp":       time.Now().Add(AccessTokenTTL).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
 }
+
+// GenerateOpaqueToken returns a random URL safe token and the hash to store it by. Only the
+// hash is persisted, so a leaked database does not leak usable tokens.
+func GenerateOpaqueToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}